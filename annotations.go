@@ -0,0 +1,216 @@
+package docgen
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Annotations is the request/response metadata declared in a handler doc comment.
+// Each annotation is a line starting with a tag, the words are separated by spaces,
+// [optional] words may be omitted and the description is the rest of the line:
+//
+//	@param    <name> <type> [description]
+//	@query    <name> <type> [required] [description]
+//	@header   <name> [required] [description]
+//	@body     <content-type> <Type> [description]
+//	@response <status> [<content-type> <Type>] [description]
+//	@tag      <name> [name...]
+//	@auth     <scheme> [scope...]
+//	@deprecated [reason]
+//
+// For example:
+//
+//	// GetArticle returns a specific Article.
+//	// @param articleID int Identifier of the Article
+//	// @response 200 application/json Article
+//	// @response 404 Article not found
+//	// @tag articles
+type Annotations struct {
+	Params     []ParamAnnotation    `json:"params,omitempty"`
+	Query      []ParamAnnotation    `json:"query,omitempty"`
+	Headers    []ParamAnnotation    `json:"headers,omitempty"`
+	Body       *BodyAnnotation      `json:"body,omitempty"`
+	Responses  []ResponseAnnotation `json:"responses,omitempty"`
+	Tags       []string             `json:"tags,omitempty"`
+	Auth       []AuthAnnotation     `json:"auth,omitempty"`
+	Deprecated *DeprecatedNote      `json:"deprecated,omitempty"`
+}
+
+// ParamAnnotation describes a path, query or header parameter.
+type ParamAnnotation struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// JSONType converts the Go-like type of the parameter into a JSON Schema type,
+// e.g. "int64" becomes "integer", unknown types are kept as is.
+func (p ParamAnnotation) JSONType() string {
+	switch p.Type {
+	case "", "string":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "integer":
+		return "integer"
+	case "float32", "float64", "number":
+		return "number"
+	case "bool", "boolean":
+		return "boolean"
+	default:
+		return p.Type
+	}
+}
+
+// BodyAnnotation describes the request body.
+type BodyAnnotation struct {
+	ContentType string  `json:"content_type"`
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"` // set by ResolveSchemas
+}
+
+// ResponseAnnotation describes a response, ContentType and Type are empty when there is no body.
+type ResponseAnnotation struct {
+	Status      int     `json:"status"`
+	ContentType string  `json:"content_type,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"` // set by ResolveSchemas
+}
+
+// AuthAnnotation is an authentication requirement, such as "bearer" or "oauth2 articles:write".
+type AuthAnnotation struct {
+	Scheme string   `json:"scheme"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// DeprecatedNote marks a deprecated handler.
+type DeprecatedNote struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ParseAnnotations extracts the annotations of a doc comment.
+// It returns the comment without the annotation lines
+// and nil Annotations when the comment has none.
+// The lines starting with an unknown @tag are kept in the comment.
+func ParseAnnotations(comment string) (string, *Annotations) {
+	var (
+		ann   Annotations
+		found bool
+		text  []string
+	)
+
+	for _, line := range strings.Split(comment, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 || !ann.parse(words) {
+			text = append(text, line)
+
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		return comment, nil
+	}
+
+	return strings.Join(text, "\n"), &ann
+}
+
+// parse adds the annotation of a comment line and reports whether it was one.
+func (ann *Annotations) parse(words []string) bool {
+	tag, args := words[0], words[1:]
+
+	switch tag {
+	case "@param":
+		if len(args) < 2 {
+			return false
+		}
+		ann.Params = append(ann.Params, ParamAnnotation{
+			Name:        args[0],
+			Type:        args[1],
+			Required:    true,
+			Description: strings.Join(args[2:], " "),
+		})
+
+	case "@query":
+		if len(args) < 2 {
+			return false
+		}
+		required, description := isRequired(args[2:])
+		ann.Query = append(ann.Query, ParamAnnotation{
+			Name:        args[0],
+			Type:        args[1],
+			Required:    required,
+			Description: description,
+		})
+
+	case "@header":
+		if len(args) < 1 {
+			return false
+		}
+		required, description := isRequired(args[1:])
+		ann.Headers = append(ann.Headers, ParamAnnotation{
+			Name:        args[0],
+			Type:        "string",
+			Required:    required,
+			Description: description,
+		})
+
+	case "@body":
+		if len(args) < 2 {
+			return false
+		}
+		ann.Body = &BodyAnnotation{
+			ContentType: args[0],
+			Type:        args[1],
+			Description: strings.Join(args[2:], " "),
+		}
+
+	case "@response":
+		if len(args) < 1 {
+			return false
+		}
+		status, err := strconv.Atoi(args[0])
+		if err != nil {
+			return false
+		}
+		resp := ResponseAnnotation{Status: status, ContentType: "", Type: "", Description: "", Schema: nil}
+		args = args[1:]
+		if len(args) >= 2 && strings.Contains(args[0], "/") {
+			resp.ContentType, resp.Type = args[0], args[1]
+			args = args[2:]
+		}
+		resp.Description = strings.Join(args, " ")
+		ann.Responses = append(ann.Responses, resp)
+
+	case "@tag":
+		if len(args) < 1 {
+			return false
+		}
+		ann.Tags = append(ann.Tags, args...)
+
+	case "@auth":
+		if len(args) < 1 {
+			return false
+		}
+		ann.Auth = append(ann.Auth, AuthAnnotation{Scheme: args[0], Scopes: args[1:]})
+
+	case "@deprecated":
+		ann.Deprecated = &DeprecatedNote{Reason: strings.Join(args, " ")}
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// isRequired consumes the optional "required" word preceding a description.
+func isRequired(args []string) (bool, string) {
+	if len(args) > 0 && args[0] == "required" {
+		return true, strings.Join(args[1:], " ")
+	}
+
+	return false, strings.Join(args, " ")
+}
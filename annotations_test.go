@@ -0,0 +1,89 @@
+package docgen_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		comment  string
+		wantText string
+		want     *docgen.Annotations
+	}{{
+		name:     "no annotation",
+		comment:  "ListArticles returns an array of Articles.\n@unknown is kept\n",
+		wantText: "ListArticles returns an array of Articles.\n@unknown is kept\n",
+		want:     nil,
+	}, {
+		name: "all annotations",
+		comment: "UpdateArticle updates an Article.\n" +
+			"@param articleID int64 Identifier of the Article\n" +
+			"@query dry bool\n" +
+			"@query lang string required Language of the title\n" +
+			"@header X-Request-ID required\n" +
+			"@body application/json ArticleRequest The new values\n" +
+			"@response 200 application/json Article\n" +
+			"@response 404 Article not found\n" +
+			"@tag articles admin\n" +
+			"@auth oauth2 articles:write\n" +
+			"@deprecated use PATCH\n",
+		wantText: "UpdateArticle updates an Article.\n",
+		want: &docgen.Annotations{
+			Params: []docgen.ParamAnnotation{
+				{Name: "articleID", Type: "int64", Required: true, Description: "Identifier of the Article"},
+			},
+			Query: []docgen.ParamAnnotation{
+				{Name: "dry", Type: "bool", Required: false, Description: ""},
+				{Name: "lang", Type: "string", Required: true, Description: "Language of the title"},
+			},
+			Headers: []docgen.ParamAnnotation{
+				{Name: "X-Request-ID", Type: "string", Required: true, Description: ""},
+			},
+			Body: &docgen.BodyAnnotation{ContentType: "application/json", Type: "ArticleRequest", Description: "The new values"},
+			Responses: []docgen.ResponseAnnotation{
+				{Status: 200, ContentType: "application/json", Type: "Article", Description: ""},
+				{Status: 404, ContentType: "", Type: "", Description: "Article not found"},
+			},
+			Tags:       []string{"articles", "admin"},
+			Auth:       []docgen.AuthAnnotation{{Scheme: "oauth2", Scopes: []string{"articles:write"}}},
+			Deprecated: &docgen.DeprecatedNote{Reason: "use PATCH"},
+		},
+	}, {
+		name:     "malformed annotation is kept as text",
+		comment:  "@response OK\n@query page\n",
+		wantText: "@response OK\n@query page\n",
+		want:     nil,
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			text, got := docgen.ParseAnnotations(c.comment)
+			if text != c.wantText {
+				t.Errorf("ParseAnnotations() text = %q, want %q", text, c.wantText)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("ParseAnnotations() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParamAnnotation_JSONType(t *testing.T) {
+	t.Parallel()
+
+	for typ, want := range map[string]string{"": "string", "int64": "integer", "float32": "number", "bool": "boolean", "uuid": "uuid"} {
+		if got := (docgen.ParamAnnotation{Name: "p", Type: typ, Required: false, Description: ""}).JSONType(); got != want {
+			t.Errorf("JSONType(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
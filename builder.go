@@ -1,58 +1,74 @@
 package docgen
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// BuildDoc builds the Doc of the router r with a new Resolver, see Resolver.BuildDoc.
 func BuildDoc(r chi.Routes) (Doc, error) {
-	d := Doc{}
-
-	if getGoPath() == "" {
-		return d, errors.New("docgen: unable to determine your $GOPATH")
-	}
-
-	// Walk and generate the router docs
-	d.Router = BuildDocRouter(r)
-
-	return d, nil
+	return (&Resolver{KeepAST: false, Workers: 0}).BuildDoc(r)
 }
 
+// BuildDocRouter builds the DocRouter of the router r with a new Resolver.
 func BuildDocRouter(r chi.Routes) DocRouter {
+	return (&Resolver{KeepAST: false, Workers: 0}).BuildDocRouter(r)
+}
+
+// buildDocRouter walks the router r, getting the FuncInfo of the handlers and middlewares from funcInfo.
+func buildDocRouter(parentPattern string, r chi.Routes, funcInfo func(any) FuncInfo) DocRouter {
 	if r == nil {
 		return DocRouter{}
 	}
 
 	rts := r
 	dr := DocRouter{
-		Middlewares: []DocMiddleware{},
-		Routes:      map[string]DocRoute{},
+		InlineMiddlewares: nil,
+		Middlewares:       []DocMiddleware{},
+		Routes:            map[string]DocRoute{},
 	}
 	dr.Routes = DocRoutes{}
 
 	for _, mw := range rts.Middlewares() {
 		dmw := DocMiddleware{
-			FuncInfo: GetFuncInfo(mw),
+			FuncInfo: funcInfo(mw),
 		}
 		dr.Middlewares = append(dr.Middlewares, dmw)
 	}
 
 	for _, rt := range rts.Routes() {
+		pattern := JoinPattern(parentPattern, rt.Pattern)
+
 		drt := DocRoute{
 			Pattern:  rt.Pattern,
+			Params:   ParsePattern(rt.Pattern),
 			Handlers: DocHandlers{},
 			Router: &DocRouter{
-				Middlewares: []DocMiddleware{},
-				Routes:      map[string]DocRoute{},
+				InlineMiddlewares: nil,
+				Middlewares:       []DocMiddleware{},
+				Routes:            map[string]DocRoute{},
 			},
 		}
 
 		if rt.SubRoutes != nil {
+			// The "/*" of a mount point is not a parameter of the route
+			drt.Params = ParsePattern(strings.TrimSuffix(rt.Pattern, "/*"))
+
 			subRoutes := rt.SubRoutes
-			subDrts := BuildDocRouter(subRoutes)
+			subDrts := buildDocRouter(pattern, subRoutes, funcInfo)
+
+			// With wraps the mount handler in the inline middlewares
+			if chain, ok := rt.Handlers["*"].(*chi.ChainHandler); ok {
+				for _, mw := range chain.Middlewares {
+					subDrts.InlineMiddlewares = append(subDrts.InlineMiddlewares, DocMiddleware{
+						FuncInfo: funcInfo(mw),
+					})
+				}
+			}
+
 			drt.Router = &subDrts
 		} else {
 			hall := rt.Handlers["*"]
@@ -64,6 +80,7 @@ func BuildDocRouter(r chi.Routes) DocRouter {
 				dh := DocHandler{
 					Middlewares: []DocMiddleware{},
 					Method:      method,
+					Params:      ParsePattern(pattern),
 					FuncInfo: FuncInfo{
 						Pkg:          "",
 						Func:         "",
@@ -81,7 +98,7 @@ func BuildDocRouter(r chi.Routes) DocRouter {
 				if chain != nil {
 					for _, mw := range chain.Middlewares {
 						dh.Middlewares = append(dh.Middlewares, DocMiddleware{
-							FuncInfo: GetFuncInfo(mw),
+							FuncInfo: funcInfo(mw),
 						})
 					}
 					endpoint = chain.Endpoint
@@ -89,7 +106,7 @@ func BuildDocRouter(r chi.Routes) DocRouter {
 					endpoint = h
 				}
 
-				dh.FuncInfo = GetFuncInfo(endpoint)
+				dh.FuncInfo = funcInfo(endpoint)
 
 				drt.Handlers[method] = dh
 			}
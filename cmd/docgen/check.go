@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// stringList is a flag which may be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+
+	return nil
+}
+
+// runCheck compares two JSON files generated by the json command,
+// writes the report of the breaking changes and returns exitBreaking if any.
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	var (
+		output, format, allowFile string
+		opts                      docgen.CompatOptions
+	)
+
+	flags := flag.NewFlagSet("docgen check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: docgen check [flags] old.json new.json")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&output, "o", "", "output file (default stdout)")
+	flags.StringVar(&format, "format", "markdown", "report format: markdown or json")
+	flags.Var((*stringList)(&opts.Allow), "allow", `accepted breaking change "<kind> <pattern> [<method>]" (repeatable)`)
+	flags.StringVar(&allowFile, "allow-file", "", "file of accepted breaking changes, one per line, # for comments")
+	flags.Var((*stringList)(&opts.SafeMiddlewares), "safe-middleware", `middleware "pkg.Func" which may be added without breaking the clients (repeatable)`)
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+
+		return exitUsage
+	}
+
+	if format != "markdown" && format != "json" {
+		fmt.Fprintln(stderr, "docgen: unknown format "+format)
+
+		return exitUsage
+	}
+
+	compat, err := check(flags.Arg(0), flags.Arg(1), allowFile, opts)
+	if err == nil {
+		var b []byte
+		if format == "json" {
+			b, err = compat.JSON()
+		} else {
+			b = compat.Markdown()
+		}
+		if err == nil {
+			err = write(b, output, stdout)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	if !compat.OK() {
+		return exitBreaking
+	}
+
+	return exitOK
+}
+
+func check(oldFile, newFile, allowFile string, opts docgen.CompatOptions) (docgen.Compatibility, error) {
+	if allowFile != "" {
+		allow, err := readAllowList(allowFile)
+		if err != nil {
+			return docgen.Compatibility{}, err
+		}
+
+		opts.Allow = append(opts.Allow, allow...)
+	}
+
+	oldDoc, err := readDoc(oldFile)
+	if err != nil {
+		return docgen.Compatibility{}, err
+	}
+
+	newDoc, err := readDoc(newFile)
+	if err != nil {
+		return docgen.Compatibility{}, err
+	}
+
+	return docgen.CheckCompatibility(docgen.Diff(oldDoc, newDoc), opts), nil
+}
+
+// readAllowList reads the non-empty lines of the file, except the comments.
+func readAllowList(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: %w", err)
+	}
+	defer f.Close()
+
+	var allow []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			allow = append(allow, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("docgen: %s: %w", file, err)
+	}
+
+	return allow, nil
+}
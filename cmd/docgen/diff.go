@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// runDiff compares two JSON files generated by the json command
+// (or docgen.JSONRoutesBytes) and writes the report of the changes.
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	var output, format string
+
+	flags := flag.NewFlagSet("docgen diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: docgen diff [flags] old.json new.json")
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&output, "o", "", "output file (default stdout)")
+	flags.StringVar(&format, "format", "markdown", "report format: markdown or json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+
+		return exitUsage
+	}
+
+	if format != "markdown" && format != "json" {
+		fmt.Fprintln(stderr, "docgen: unknown format "+format)
+
+		return exitUsage
+	}
+
+	b, err := diff(flags.Arg(0), flags.Arg(1), format)
+	if err == nil {
+		err = write(b, output, stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+func diff(oldFile, newFile, format string) ([]byte, error) {
+	oldDoc, err := readDoc(oldFile)
+	if err != nil {
+		return nil, err
+	}
+
+	newDoc, err := readDoc(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	d := docgen.Diff(oldDoc, newDoc)
+	if format == "json" {
+		return d.JSON()
+	}
+
+	return d.Markdown(), nil
+}
+
+// readDoc reads a Doc from a JSON file.
+func readDoc(file string) (docgen.Doc, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return docgen.Doc{}, fmt.Errorf("docgen: %w", err)
+	}
+
+	doc, err := docgen.ParseJSON(b)
+	if err != nil {
+		return doc, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return doc, nil
+}
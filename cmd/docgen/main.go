@@ -0,0 +1,260 @@
+// Command docgen generates the documentation of a chi router:
+//
+//	docgen <command> [flags] [package]
+//
+// The commands are json, markdown, html, raml, openapi, routes and diagram.
+// The explain command prints the route, the URL parameters, the middlewares
+// and the handler serving a request, see docgen.Explain:
+//
+//	docgen explain [flags] -url /articles/42 [package]
+//
+// The diff command compares two JSON files generated by the json command,
+// and the check command reports their breaking changes:
+//
+//	docgen diff [flags] old.json new.json
+//	docgen check [flags] old.json new.json
+//
+// The package (default ".") exposes a router constructor, such as
+// "func NewRouter() chi.Router", called by a temporary main built
+// in the module of the package, which must require docgen-yes.
+// The main is written outside the source tree, see go help build -overlay.
+// With -static, the package is analyzed without running the constructor,
+// see docgen.BuildDocFromSource. With -from, the documentation is generated
+// from a JSON file generated by the json command, see docgen.ParseJSON.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// Exit codes.
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitBreaking = 3 // check command: breaking changes found
+)
+
+var commands = []string{"json", "markdown", "html", "raml", "openapi", "routes", "diagram", "explain", "diff", "check"}
+
+// options of a command.
+type options struct {
+	command string
+	dir     string
+	pkg     string
+	fn      string
+	output  string
+	format  string
+	title   string
+	intro   string
+	from    string
+	method  string              // explain command
+	url     string              // explain command
+	printer docgen.RoutePrinter // routes command
+	static  bool
+	schemas bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || !isCommand(args[0]) {
+		usage(stderr)
+
+		return exitUsage
+	}
+
+	if args[0] == "diff" {
+		return runDiff(args[1:], stdout, stderr)
+	}
+
+	if args[0] == "check" {
+		return runCheck(args[1:], stdout, stderr)
+	}
+
+	opts, err := parse(args[0], args[1:], stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, err)
+		}
+
+		return exitUsage
+	}
+
+	var b []byte
+	if opts.static || opts.from != "" {
+		b, err = generateStatic(opts)
+	} else {
+		b, err = generateRuntime(opts, stderr)
+	}
+	if err == nil {
+		err = write(b, opts.output, stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+// write writes the generated documentation to the output file, or stdout when empty.
+func write(b []byte, output string, stdout io.Writer) error {
+	if output != "" {
+		return os.WriteFile(output, b, 0o644)
+	}
+
+	_, err := stdout.Write(b)
+
+	return err
+}
+
+func parse(command string, args []string, stderr io.Writer) (options, error) {
+	opts := options{
+		command: command,
+		dir:     "",
+		pkg:     ".",
+		fn:      "",
+		output:  "",
+		format:  "",
+		title:   "",
+		intro:   "",
+		from:    "",
+		method:  "",
+		url:     "",
+		printer: docgen.RoutePrinter{
+			Writer:      nil,
+			Tree:        false,
+			Methods:     false,
+			Handlers:    false,
+			Middlewares: false,
+			Color:       false,
+			Align:       true,
+			MethodOrder: nil,
+		},
+		static:  false,
+		schemas: false,
+	}
+
+	flags := flag.NewFlagSet("docgen "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: docgen %s [flags] [package]\n", command)
+		flags.PrintDefaults()
+	}
+
+	flags.StringVar(&opts.dir, "C", ".", "directory of the Go module")
+	flags.StringVar(&opts.fn, "func", "NewRouter", "function of the package returning the chi router")
+	flags.StringVar(&opts.output, "o", "", "output file (default stdout)")
+	flags.StringVar(&opts.title, "title", "", "title of the documentation")
+	flags.StringVar(&opts.intro, "intro", "", "introduction text of markdown and html")
+	flags.BoolVar(&opts.static, "static", false, "analyze the source code instead of running the router constructor")
+	flags.StringVar(&opts.from, "from", "", "JSON file generated by the json command, instead of the package")
+	flags.BoolVar(&opts.schemas, "schemas", false, "resolve the Go types of the @body and @response annotations (json, markdown, html, openapi, raml)")
+	switch command {
+	case "openapi":
+		flags.StringVar(&opts.format, "format", "json", "output format: json or yaml")
+	case "diagram":
+		flags.StringVar(&opts.format, "format", "mermaid", "output format: mermaid or dot")
+	case "routes":
+		flags.BoolVar(&opts.printer.Tree, "tree", false, "print the tree of the routers instead of the full patterns")
+		flags.BoolVar(&opts.printer.Methods, "methods", false, "print the method of each handler")
+		flags.BoolVar(&opts.printer.Handlers, "handlers", false, "print the function and the file:line of each handler")
+		flags.BoolVar(&opts.printer.Middlewares, "middlewares", false, "print the middlewares of each handler")
+		flags.BoolVar(&opts.printer.Color, "color", false, "color the output with ANSI escape codes")
+	case "explain":
+		flags.StringVar(&opts.format, "format", "text", "output format: text or json")
+		flags.StringVar(&opts.method, "method", "GET", "method of the request")
+		flags.StringVar(&opts.url, "url", "", "URL of the request, e.g. /articles/42?page=2")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		opts.pkg = flags.Arg(0)
+	default:
+		flags.Usage()
+
+		return opts, errors.New("docgen: a single package is expected")
+	}
+
+	if !token.IsIdentifier(opts.fn) {
+		return opts, errors.New("docgen: invalid function name " + opts.fn)
+	}
+
+	if !validFormat(command, opts.format) {
+		return opts, errors.New("docgen: unknown format " + opts.format)
+	}
+
+	if opts.static && opts.from != "" {
+		return opts, errors.New("docgen: -static and -from are exclusive")
+	}
+
+	if command == "explain" {
+		if opts.url == "" {
+			return opts, errors.New("docgen: explain needs the -url of the request")
+		}
+		if opts.static || opts.from != "" {
+			return opts, errors.New("docgen: explain runs the router, -static and -from are not supported")
+		}
+	}
+
+	return opts, nil
+}
+
+// validFormat reports whether the -format of the command is valid.
+func validFormat(command, format string) bool {
+	switch command {
+	case "openapi":
+		return format == "json" || format == "yaml"
+	case "diagram":
+		return format == docgen.DiagramMermaid || format == docgen.DiagramDOT
+	case "explain":
+		return format == "text" || format == "json"
+	default:
+		return format == ""
+	}
+}
+
+func isCommand(name string) bool {
+	for _, c := range commands {
+		if name == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `Usage: docgen <command> [flags] [package]
+
+Commands:
+  json      JSON of the docgen.Doc
+  markdown  Markdown documentation
+  html      HTML documentation
+  raml      RAML 1.0 specification
+  openapi   OpenAPI 3 specification
+  routes    list or tree of the routes, with their methods, handlers and middlewares
+  diagram   Mermaid or Graphviz diagram of the routers and middlewares
+  explain   route, URL parameters, middlewares and handler serving a request
+  diff      changes between two JSON files generated by the json command
+  check     breaking changes between two JSON files generated by the json command
+
+Run "docgen <command> -h" for the flags of a command.`)
+}
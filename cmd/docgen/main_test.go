@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+
+	fixture := []string{"-C", "../..", "-func", "Router", "./testdata/articles"}
+
+	cases := []struct {
+		name     string
+		args     []string
+		code     int
+		contains string
+	}{{
+		name:     "routes",
+		args:     append([]string{"routes"}, fixture...),
+		code:     exitOK,
+		contains: "/articles/*/{articleID:[0-9]+}/*/\n",
+	}, {
+		name:     "routes tree",
+		args:     append([]string{"routes", "-tree", "-methods", "-handlers"}, fixture...),
+		code:     exitOK,
+		contains: "├── /articles/*\n│   ├── /  ",
+	}, {
+		name:     "openapi yaml",
+		args:     append([]string{"openapi", "-format", "yaml", "-title", "Articles"}, fixture...),
+		code:     exitOK,
+		contains: "title: Articles",
+	}, {
+		name:     "static routes",
+		args:     append([]string{"routes", "-static"}, fixture...),
+		code:     exitOK,
+		contains: "/articles/*/{articleID:[0-9]+}/*/\n",
+	}, {
+		name:     "static routes middlewares",
+		args:     append([]string{"routes", "-static", "-methods", "-middlewares"}, fixture...),
+		code:     exitOK,
+		contains: "GET    middleware.RequestID → articles.Paginate\n",
+	}, {
+		name:     "static markdown",
+		args:     append([]string{"markdown", "-static"}, fixture...),
+		code:     exitOK,
+		contains: "<summary>`/articles/*/{articleID:[0-9]+}/*`</summary>",
+	}, {
+		name:     "static openapi",
+		args:     append([]string{"openapi", "-static"}, fixture...),
+		code:     exitOK,
+		contains: `"/articles/{articleID}": {`,
+	}, {
+		name:     "unresolved schema",
+		args:     append([]string{"markdown", "-schemas"}, fixture...),
+		code:     exitError,
+		contains: `cannot resolve type "Missing"`,
+	}, {
+		name:     "json unresolved schema",
+		args:     append([]string{"json", "-schemas"}, fixture...),
+		code:     exitError,
+		contains: `cannot resolve type "Missing"`,
+	}, {
+		name:     "static raml",
+		args:     append([]string{"raml", "-static"}, fixture...),
+		code:     exitOK,
+		contains: "/{articleID}:",
+	}, {
+		name:     "diagram",
+		args:     append([]string{"diagram"}, fixture...),
+		code:     exitOK,
+		contains: "flowchart LR\n",
+	}, {
+		name:     "static diagram dot",
+		args:     append([]string{"diagram", "-static", "-format", "dot"}, fixture...),
+		code:     exitOK,
+		contains: "digraph docgen {\n",
+	}, {
+		name:     "diagram invalid format",
+		args:     append([]string{"diagram", "-format", "svg"}, fixture...),
+		code:     exitUsage,
+		contains: "unknown format svg",
+	}, {
+		name:     "explain",
+		args:     append([]string{"explain", "-url", "/articles/42/"}, fixture...),
+		code:     exitOK,
+		contains: "pattern     /articles/{articleID:[0-9]+}/\n",
+	}, {
+		name:     "explain json",
+		args:     append([]string{"explain", "-format", "json", "-method", "post", "-url", "/articles/"}, fixture...),
+		code:     exitOK,
+		contains: `"func": "CreateArticle",`,
+	}, {
+		name:     "explain no route",
+		args:     append([]string{"explain", "-method", "DELETE", "-url", "/ping"}, fixture...),
+		code:     exitError,
+		contains: "docgen: explain DELETE /ping: no route matches",
+	}, {
+		name:     "explain static",
+		args:     append([]string{"explain", "-static", "-url", "/ping"}, fixture...),
+		code:     exitUsage,
+		contains: "-static and -from are not supported",
+	}, {
+		name:     "from markdown",
+		args:     []string{"markdown", "-from", "testdata/new.json"},
+		code:     exitOK,
+		contains: "`/health`",
+	}, {
+		name:     "from raml",
+		args:     []string{"raml", "-from", "testdata/new.json"},
+		code:     exitOK,
+		contains: "/health:",
+	}, {
+		name:     "diff",
+		args:     []string{"diff", "testdata/old.json", "testdata/new.json"},
+		code:     exitOK,
+		contains: "| method-removed | `/articles` | POST |  |  |\n",
+	}, {
+		name:     "diff json",
+		args:     []string{"diff", "-format", "json", "testdata/old.json", "testdata/new.json"},
+		code:     exitOK,
+		contains: `"kind": "route-added",`,
+	}, {
+		name:     "diff missing file",
+		args:     []string{"diff", "testdata/old.json", "testdata/missing.json"},
+		code:     exitError,
+		contains: "missing.json",
+	}, {
+		name:     "diff single file",
+		args:     []string{"diff", "testdata/old.json"},
+		code:     exitUsage,
+		contains: "Usage: docgen diff",
+	}, {
+		name:     "check breaking",
+		args:     []string{"check", "testdata/old.json", "testdata/new.json"},
+		code:     exitBreaking,
+		contains: "3 breaking changes.",
+	}, {
+		name: "check allowed",
+		args: []string{
+			"check", "-allow-file", "testdata/allow.txt", "-safe-middleware", "example.com/api.Paginate",
+			"testdata/old.json", "testdata/new.json",
+		},
+		code:     exitOK,
+		contains: "No breaking changes.",
+	}, {
+		name:     "check json",
+		args:     []string{"check", "-format", "json", "-allow", "* /ping", "testdata/old.json", "testdata/new.json"},
+		code:     exitBreaking,
+		contains: `"kind": "middlewares-changed",`,
+	}, {
+		name:     "no command",
+		args:     []string{},
+		code:     exitUsage,
+		contains: "Usage: docgen <command>",
+	}, {
+		name:     "unknown command",
+		args:     []string{"pdf"},
+		code:     exitUsage,
+		contains: "Usage: docgen <command>",
+	}, {
+		name:     "unknown format",
+		args:     []string{"openapi", "-format", "xml"},
+		code:     exitUsage,
+		contains: "unknown format xml",
+	}, {
+		name:     "invalid function",
+		args:     []string{"json", "-func", "Router()"},
+		code:     exitUsage,
+		contains: "invalid function name",
+	}, {
+		name:     "static from",
+		args:     []string{"raml", "-static", "-from", "testdata/old.json"},
+		code:     exitUsage,
+		contains: "-static and -from are exclusive",
+	}, {
+		name:     "unknown function",
+		args:     []string{"json", "-static", "-C", "../..", "-func", "NewRouter", "./testdata/articles"},
+		code:     exitError,
+		contains: "function NewRouter not found",
+	}, {
+		name:     "not a router",
+		args:     []string{"json", "-C", "../..", "-func", "Paginate", "./testdata/articles"},
+		code:     exitError,
+		contains: "not enough arguments in call to target.Paginate",
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			code := run(c.args, &stdout, &stderr)
+
+			if code != c.code {
+				t.Errorf("run() = %d, want %d, stderr: %s", code, c.code, stderr.String())
+			}
+			if out := stdout.String() + stderr.String(); !strings.Contains(out, c.contains) {
+				t.Errorf("run() output should contain %q, got:\n%s", c.contains, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// generateRuntime generates the documentation by running a temporary main which calls
+// the router constructor. The main is written in a temporary directory, and an overlay
+// makes the go command see it in the module of the package, so the source tree is untouched.
+func generateRuntime(opts options, stderr io.Writer) ([]byte, error) {
+	importPath, moduleDir, err := locate(opts.dir, opts.pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := mainSource(opts, importPath, moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "docgen")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	mainFile := filepath.Join(moduleDir, "_docgen", "main.go") // only in the overlay
+	overlay, err := writeOverlay(tmp, mainFile, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("go", "run", "-overlay", overlay, mainFile)
+	cmd.Dir = moduleDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("docgen: running %s.%s: %w", importPath, opts.fn, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// writeOverlay writes the source of the main in dir, and the overlay replacing
+// the file mainFile by it, returning the path of the overlay for go run -overlay.
+func writeOverlay(dir, mainFile string, src []byte) (string, error) {
+	backing := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(backing, src, 0o600); err != nil {
+		return "", fmt.Errorf("docgen: %w", err)
+	}
+
+	b, err := json.Marshal(map[string]map[string]string{"Replace": {mainFile: backing}})
+	if err != nil {
+		return "", fmt.Errorf("docgen: json.Marshal: %w", err)
+	}
+
+	overlay := filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(overlay, b, 0o600); err != nil {
+		return "", fmt.Errorf("docgen: %w", err)
+	}
+
+	return overlay, nil
+}
+
+// locate returns the import path of the package and the directory of its module.
+func locate(dir, pkg string) (importPath, moduleDir string, err error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}\n{{with .Module}}{{.Dir}}{{end}}", pkg)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("docgen: go list %s: %w\n%s", pkg, err, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || lines[1] == "" {
+		return "", "", fmt.Errorf("docgen: package %s is not in a Go module", pkg)
+	}
+
+	return lines[0], lines[1], nil
+}
+
+// mainSource returns the source of the temporary main.
+func mainSource(opts options, importPath, moduleDir string) ([]byte, error) {
+	schemaDir := ""
+	if opts.schemas {
+		schemaDir = moduleDir
+	}
+
+	var buf bytes.Buffer
+	err := mainTemplate.Execute(&buf, map[string]string{
+		"Command":     opts.command,
+		"ImportPath":  importPath,
+		"Func":        opts.fn,
+		"Format":      opts.format,
+		"Title":       opts.title,
+		"Intro":       opts.intro,
+		"SchemaDir":   schemaDir,
+		"Method":      opts.method,
+		"URL":         opts.url,
+		"Tree":        strconv.FormatBool(opts.printer.Tree),
+		"Methods":     strconv.FormatBool(opts.printer.Methods),
+		"Handlers":    strconv.FormatBool(opts.printer.Handlers),
+		"Middlewares": strconv.FormatBool(opts.printer.Middlewares),
+		"Color":       strconv.FormatBool(opts.printer.Color),
+		"Align":       strconv.FormatBool(opts.printer.Align),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("docgen: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("docgen: invalid router constructor %q: %w", opts.fn, err)
+	}
+
+	return src, nil
+}
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by docgen; DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+{{- if eq .Command "openapi"}}
+	"github.com/teal-finance/docgen-yes/openapi"
+{{- else if eq .Command "raml"}}
+	"github.com/teal-finance/docgen-yes/raml"
+{{- else}}
+	"github.com/teal-finance/docgen-yes"
+{{- end}}
+
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	r, ok := any(target.{{.Func}}()).(chi.Routes)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "docgen: {{.Func}} does not return a chi router")
+		os.Exit(1)
+	}
+
+	b, err := generate(r)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Stdout.Write(b)
+}
+
+func generate(r chi.Routes) ([]byte, error) {
+{{- if or (eq .Command "json") (eq .Command "html")}}
+	doc, err := docgen.BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+{{- if .SchemaDir}}
+
+	if err := docgen.ResolveSchemas(&doc, {{printf "%q" .SchemaDir}}); err != nil {
+		return nil, err
+	}
+{{- end}}
+{{- if eq .Command "json"}}
+
+	return docgen.JSONGenerator{}.GenerateDoc(doc)
+{{- else}}
+
+	return docgen.MarkupGenerator{Opts: docgen.MarkupOpts{
+		ProjectPath: {{printf "%q" .Title}},
+		Intro:       {{printf "%q" .Intro}},
+	}}.GenerateDoc(doc)
+{{- end}}
+{{- else if eq .Command "markdown"}}
+	return docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{
+		ProjectPath: {{printf "%q" .Title}},
+		Intro:       {{printf "%q" .Intro}},
+		SchemaDir:   {{printf "%q" .SchemaDir}},
+	}}.Generate(r)
+{{- else if eq .Command "openapi"}}
+	api, err := openapi.FromRouter(r, openapi.Options{
+		Title:       {{printf "%q" .Title}},
+		Description: {{printf "%q" .Intro}},
+		SchemaDir:   {{printf "%q" .SchemaDir}},
+	})
+	if err != nil {
+		return nil, err
+	}
+{{- if eq .Format "yaml"}}
+	return api.YAML()
+{{- else}}
+	return api.JSON()
+{{- end}}
+{{- else if eq .Command "raml"}}
+	doc, err := raml.FromRouter(r, raml.Options{
+		Title:     {{printf "%q" .Title}},
+		SchemaDir: {{printf "%q" .SchemaDir}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(doc.String()), nil
+{{- else if eq .Command "diagram"}}
+	return docgen.DiagramGenerator{Format: {{printf "%q" .Format}}}.Generate(r)
+{{- else if eq .Command "explain"}}
+	e, err := docgen.Explain(r, {{printf "%q" .Method}}, {{printf "%q" .URL}})
+	if err != nil {
+		return nil, err
+	}
+{{- if eq .Format "json"}}
+	return e.JSON()
+{{- else}}
+	return e.Text(), nil
+{{- end}}
+{{- else}}
+	return docgen.RoutePrinter{
+		Tree:        {{.Tree}},
+		Methods:     {{.Methods}},
+		Handlers:    {{.Handlers}},
+		Middlewares: {{.Middlewares}},
+		Color:       {{.Color}},
+		Align:       {{.Align}},
+	}.Generate(r)
+{{- end}}
+}
+`))
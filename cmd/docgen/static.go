@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/openapi"
+	"github.com/teal-finance/docgen-yes/raml"
+)
+
+// generateStatic generates the documentation from the source code, without running it,
+// or from the JSON snapshot of the -from flag.
+func generateStatic(opts options) ([]byte, error) {
+	var (
+		doc docgen.Doc
+		err error
+	)
+	if opts.from != "" {
+		doc, err = readDoc(opts.from)
+	} else {
+		doc, err = docgen.BuildDocFromSource(opts.dir, opts.pkg, opts.fn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.schemas {
+		if err := docgen.ResolveSchemas(&doc, opts.dir); err != nil {
+			return nil, err
+		}
+	}
+
+	switch opts.command {
+	case "json":
+		return docgen.JSONGenerator{}.GenerateDoc(doc)
+
+	case "markdown":
+		return docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{
+			ProjectPath:        opts.title,
+			Intro:              opts.intro,
+			ForceRelativeLinks: false,
+			URLMap:             nil,
+			SchemaDir:          "", // already resolved
+			MethodOrder:        nil,
+			Diagram:            false,
+			Template:           nil,
+		}}.GenerateDoc(doc)
+
+	case "html":
+		return docgen.MarkupGenerator{Opts: docgen.MarkupOpts{
+			ProjectPath:        opts.title,
+			Intro:              opts.intro,
+			RouteText:          "",
+			ForceRelativeLinks: false,
+			URLMap:             nil,
+			MethodOrder:        nil,
+			Theme:              "",
+			CSS:                "",
+			Diagram:            false,
+			MermaidURL:         "",
+			Explorer:           false,
+			TryItOut:           false,
+			Template:           nil,
+		}}.GenerateDoc(doc)
+
+	case "openapi":
+		api := openapi.FromDoc(doc, openapi.Options{
+			Title:           opts.title,
+			Description:     opts.intro,
+			Version:         "",
+			Servers:         nil,
+			SecuritySchemes: nil,
+			SchemaDir:       "",
+		})
+		if opts.format == "yaml" {
+			return api.YAML()
+		}
+
+		return api.JSON()
+
+	case "raml":
+		return raml.Generator{Opts: raml.Options{
+			Title:           opts.title,
+			BaseURI:         "",
+			Protocols:       nil,
+			MediaType:       "",
+			Version:         "",
+			Documentation:   nil,
+			SecuritySchemes: nil,
+			SchemaDir:       "",
+		}}.GenerateDoc(doc)
+
+	case "routes":
+		return opts.printer.GenerateDoc(doc)
+
+	case "diagram":
+		return docgen.DiagramGenerator{Format: opts.format, MethodOrder: nil}.GenerateDoc(doc)
+
+	default:
+		return nil, errors.New("docgen: unknown command " + opts.command)
+	}
+}
@@ -0,0 +1,184 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CompatOptions configures CheckCompatibility.
+type CompatOptions struct {
+	// Allow lists the accepted breaking changes as "<kind> <pattern> [<method>]",
+	// e.g. "route-removed /v1/ping" or "method-removed /articles DELETE".
+	// The kind may be "*" and a pattern ending with "*" matches the patterns
+	// starting with the same prefix, e.g. "* /internal/*".
+	Allow []string
+
+	// SafeMiddlewares lists the middlewares, as "pkg.Func", which may be added
+	// to a route without breaking its clients, e.g. logging or compression.
+	// Adding any other middleware, such as an authentication, is breaking.
+	SafeMiddlewares []string
+}
+
+// Compatibility classifies the changes of a DocDiff.
+type Compatibility struct {
+	Breaking    []Change `json:"breaking"`
+	Allowed     []Change `json:"allowed"` // breaking changes matching CompatOptions.Allow
+	NonBreaking []Change `json:"non_breaking"`
+}
+
+// CheckCompatibility classifies the changes between two Docs as breaking or not.
+// Removing a route or a method, changing the parameters of a route and adding a
+// middleware not listed in opts.SafeMiddlewares are breaking changes.
+// Adding a route or a method, changing a handler function and removing a middleware are not.
+func CheckCompatibility(d DocDiff, opts CompatOptions) Compatibility {
+	compat := Compatibility{
+		Breaking:    []Change{},
+		Allowed:     []Change{},
+		NonBreaking: []Change{},
+	}
+
+	for _, c := range d.Changes {
+		switch {
+		case !isBreaking(c, opts.SafeMiddlewares):
+			compat.NonBreaking = append(compat.NonBreaking, c)
+		case isAllowed(c, opts.Allow):
+			compat.Allowed = append(compat.Allowed, c)
+		default:
+			compat.Breaking = append(compat.Breaking, c)
+		}
+	}
+
+	return compat
+}
+
+// OK reports whether no breaking change is left after the allow-list.
+func (c Compatibility) OK() bool {
+	return len(c.Breaking) == 0
+}
+
+// JSON returns the indented JSON report of the classified changes.
+func (c Compatibility) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: json.MarshalIndent: %w", err)
+	}
+
+	return b, nil
+}
+
+// Markdown returns the report of the classified changes, a table per class.
+func (c Compatibility) Markdown() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# API compatibility\n\n")
+
+	if c.OK() {
+		buf.WriteString("No breaking changes.\n")
+	} else {
+		fmt.Fprintf(&buf, "%d breaking changes.\n", len(c.Breaking))
+	}
+
+	for _, section := range []struct {
+		title   string
+		changes []Change
+	}{
+		{"Breaking changes", c.Breaking},
+		{"Allowed breaking changes", c.Allowed},
+		{"Non-breaking changes", c.NonBreaking},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+
+		buf.WriteString("\n## " + section.title + "\n\n")
+		writeChanges(&buf, section.changes)
+	}
+
+	return buf.Bytes()
+}
+
+// isBreaking reports whether the change may break the clients of the route.
+func isBreaking(c Change, safeMiddlewares []string) bool {
+	switch c.Kind {
+	case RouteRemoved, MethodRemoved, ParamsChanged:
+		return true
+	case MiddlewaresChanged:
+		for _, name := range addedNames(c.Old, c.New) {
+			if !contains(safeMiddlewares, name) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+// isAllowed reports whether the change matches an entry of the allow-list.
+func isAllowed(c Change, allow []string) bool {
+	for _, entry := range allow {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 || len(fields) > 3 {
+			continue
+		}
+
+		if fields[0] != "*" && fields[0] != string(c.Kind) {
+			continue
+		}
+
+		if !matchPattern(fields[1], c.Pattern) {
+			continue
+		}
+
+		if len(fields) == 3 && fields[2] != c.Method {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// matchPattern reports whether the route pattern is the allowed one,
+// or starts with its prefix when the allowed pattern ends with "*".
+func matchPattern(allowed, pattern string) bool {
+	if strings.HasSuffix(allowed, "*") && strings.HasPrefix(pattern, strings.TrimSuffix(allowed, "*")) {
+		return true
+	}
+
+	return allowed == pattern
+}
+
+// addedNames returns the names of newNames missing in oldNames,
+// taking into account the names present several times.
+func addedNames(oldNames, newNames []string) []string {
+	count := make(map[string]int, len(oldNames))
+	for _, name := range oldNames {
+		count[name]++
+	}
+
+	var added []string
+	for _, name := range newNames {
+		if count[name] > 0 {
+			count[name]--
+		} else {
+			added = append(added, name)
+		}
+	}
+
+	return added
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,116 @@
+package docgen_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestDiff_params(t *testing.T) {
+	t.Parallel()
+
+	newDoc := oldDoc()
+	articles := newDoc.Router.Routes["/articles/*"].Router
+	delete(articles.Routes, "/{id}")
+	articles.Routes["/{articleID}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{"GET": handler("GET", "GetArticle")}}
+
+	want := []docgen.Change{{
+		Kind:    docgen.ParamsChanged,
+		Pattern: "/articles/{articleID}",
+		Old:     []string{"/articles/{id}"},
+		New:     []string{"/articles/{articleID}"},
+	}}
+
+	got := docgen.Diff(oldDoc(), newDoc)
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("Diff() = %+v\nwant %+v", got.Changes, want)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	newDoc := oldDoc()
+	delete(newDoc.Router.Routes, "/ping")
+	newDoc.Router.Routes["/health"] = docgen.DocRoute{Handlers: docgen.DocHandlers{"GET": handler("GET", "Health")}}
+	articles := newDoc.Router.Routes["/articles/*"].Router
+	articles.Routes["/"] = docgen.DocRoute{Handlers: docgen.DocHandlers{
+		"GET":  handler("GET", "ListArticles", "Paginate"),
+		"POST": handler("POST", "CreateArticle", "Auth"),
+	}}
+	delete(articles.Routes, "/{id}")
+	articles.Routes["/{articleID}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{"GET": handler("GET", "ShowArticle")}}
+
+	d := docgen.Diff(oldDoc(), newDoc)
+
+	cases := []struct {
+		name        string
+		opts        docgen.CompatOptions
+		breaking    []docgen.ChangeKind
+		allowed     []docgen.ChangeKind
+		nonBreaking []docgen.ChangeKind
+	}{{
+		name:        "default",
+		opts:        docgen.CompatOptions{Allow: nil, SafeMiddlewares: nil},
+		breaking:    []docgen.ChangeKind{docgen.MiddlewaresChanged, docgen.MiddlewaresChanged, docgen.ParamsChanged, docgen.RouteRemoved},
+		allowed:     []docgen.ChangeKind{},
+		nonBreaking: []docgen.ChangeKind{docgen.HandlerChanged, docgen.RouteAdded},
+	}, {
+		name: "allowed",
+		opts: docgen.CompatOptions{
+			Allow:           []string{"* /articles/*", "route-removed /ping GET", "invalid"},
+			SafeMiddlewares: []string{"example.com/api.Paginate"},
+		},
+		breaking:    []docgen.ChangeKind{docgen.RouteRemoved},
+		allowed:     []docgen.ChangeKind{docgen.MiddlewaresChanged, docgen.ParamsChanged},
+		nonBreaking: []docgen.ChangeKind{docgen.MiddlewaresChanged, docgen.HandlerChanged, docgen.RouteAdded},
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := docgen.CheckCompatibility(d, c.opts)
+			if k := kinds(got.Breaking); !reflect.DeepEqual(k, c.breaking) {
+				t.Errorf("Breaking = %v, want %v", k, c.breaking)
+			}
+			if k := kinds(got.Allowed); !reflect.DeepEqual(k, c.allowed) {
+				t.Errorf("Allowed = %v, want %v", k, c.allowed)
+			}
+			if k := kinds(got.NonBreaking); !reflect.DeepEqual(k, c.nonBreaking) {
+				t.Errorf("NonBreaking = %v, want %v", k, c.nonBreaking)
+			}
+			if got.OK() {
+				t.Error("OK() = true, want false")
+			}
+		})
+	}
+}
+
+func TestCompatibility_Markdown(t *testing.T) {
+	t.Parallel()
+
+	d := docgen.Diff(oldDoc(), oldDoc())
+	compat := docgen.CheckCompatibility(d, docgen.CompatOptions{Allow: nil, SafeMiddlewares: nil})
+
+	if !compat.OK() {
+		t.Errorf("OK() = false, breaking changes: %+v", compat.Breaking)
+	}
+
+	if md := string(compat.Markdown()); !strings.Contains(md, "No breaking changes.") || strings.Contains(md, "##") {
+		t.Errorf("Markdown() = %s", md)
+	}
+}
+
+func kinds(changes []docgen.Change) []docgen.ChangeKind {
+	k := make([]docgen.ChangeKind, 0, len(changes))
+	for _, c := range changes {
+		k = append(k, c.Kind)
+	}
+
+	return k
+}
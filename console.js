@@ -0,0 +1,87 @@
+// Try-it-out console of the HTML page generated with MarkupOpts.TryItOut:
+// sends the request of a handler form to the same origin and shows the response.
+(function () {
+  "use strict";
+
+  // lines splits a textarea into its non-empty lines.
+  function lines(text) {
+    return text.split("\n").map(function (line) { return line.trim(); }).filter(Boolean);
+  }
+
+  // url replaces the path parameters of the pattern and appends the query.
+  function url(form) {
+    var path = form.dataset.pattern;
+    form.querySelectorAll("input[data-placeholder]").forEach(function (input) {
+      var placeholder = input.dataset.placeholder;
+      var value = placeholder === "*" ? encodeURI(input.value) : encodeURIComponent(input.value);
+      var i = path.indexOf(placeholder);
+      if (i >= 0) {
+        path = path.slice(0, i) + value + path.slice(i + placeholder.length);
+      }
+    });
+
+    var query = new URLSearchParams(form.elements.query.value.replace(/^\?/, ""));
+    form.querySelectorAll("input[data-query]").forEach(function (input) {
+      if (input.value !== "") {
+        query.append(input.dataset.query, input.value);
+      }
+    });
+    query = query.toString();
+
+    return path + (query ? "?" + query : "");
+  }
+
+  // request returns the fetch options of the form.
+  function request(form) {
+    var method = form.elements.method ? form.elements.method.value.toUpperCase() : form.dataset.method;
+    var headers = new Headers();
+    lines(form.elements.headers.value).forEach(function (line) {
+      var i = line.indexOf(":");
+      if (i > 0) {
+        headers.append(line.slice(0, i).trim(), line.slice(i + 1).trim());
+      }
+    });
+    form.querySelectorAll("input[data-header]").forEach(function (input) {
+      if (input.value !== "") {
+        headers.set(input.dataset.header, input.value);
+      }
+    });
+
+    var init = {method: method, headers: headers, credentials: "same-origin"};
+    var body = form.elements.body.value;
+    if (body !== "" && method !== "GET" && method !== "HEAD") {
+      if (!headers.has("Content-Type") && form.dataset.contentType) {
+        headers.set("Content-Type", form.dataset.contentType);
+      }
+      init.body = body;
+    }
+
+    return init;
+  }
+
+  function send(event) {
+    event.preventDefault();
+    var form = event.target;
+    var output = form.querySelector(".response");
+    var target = url(form);
+    var init = request(form);
+
+    output.hidden = false;
+    output.textContent = init.method + " " + target + "\n…";
+
+    fetch(target, init).then(function (resp) {
+      var text = init.method + " " + target + "\n\n" + resp.status + " " + resp.statusText + "\n";
+      resp.headers.forEach(function (value, name) { text += name + ": " + value + "\n"; });
+
+      return resp.text().then(function (body) {
+        output.textContent = text + "\n" + body;
+      });
+    }).catch(function (err) {
+      output.textContent = init.method + " " + target + "\n\n" + err;
+    });
+  }
+
+  document.querySelectorAll("form.console").forEach(function (form) {
+    form.addEventListener("submit", send);
+  });
+})();
@@ -0,0 +1,267 @@
+package docgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Formats of DiagramGenerator.
+const (
+	DiagramMermaid = "mermaid"
+	DiagramDOT     = "dot"
+)
+
+// DiagramGenerator draws the router tree: the routers are clusters,
+// their middlewares are chained nodes on the edges from the mount point
+// to the routes, and the handlers are the leaves.
+type DiagramGenerator struct {
+	// Format is DiagramMermaid (default), a flowchart rendered by GitHub and GitLab,
+	// or DiagramDOT for Graphviz: dot -Tsvg -o routes.svg
+	Format string
+
+	// MethodOrder sorts the methods of a route, DefaultMethodOrder when nil.
+	MethodOrder []string
+}
+
+// Generate implements Generator.
+func (g DiagramGenerator) Generate(r chi.Routes) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("docgen: router is nil")
+	}
+
+	doc, err := BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GenerateDoc(doc)
+}
+
+// GenerateDoc implements DocGenerator.
+func (g DiagramGenerator) GenerateDoc(doc Doc) ([]byte, error) {
+	d := newDiagram(doc, g.MethodOrder)
+
+	switch g.Format {
+	case "", DiagramMermaid:
+		return d.mermaid(), nil
+	case DiagramDOT:
+		return d.dot(), nil
+	default:
+		return nil, fmt.Errorf("docgen: unknown diagram format %q, want %q or %q", g.Format, DiagramMermaid, DiagramDOT)
+	}
+}
+
+// diagram is the graph drawn by DiagramGenerator.
+type diagram struct {
+	root  *diagramCluster
+	edges []diagramEdge
+	count int // number of nodes and clusters, to name them
+}
+
+// diagramCluster is a router.
+type diagramCluster struct {
+	id       string
+	label    string
+	nodes    []diagramNode
+	clusters []*diagramCluster
+}
+
+type diagramNode struct {
+	id    string
+	label []string // lines
+	kind  diagramNodeKind
+}
+
+type diagramNodeKind int
+
+const (
+	routerNode diagramNodeKind = iota
+	middlewareNode
+	handlerNode
+)
+
+type diagramEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+func newDiagram(doc Doc, methodOrder []string) *diagram {
+	d := &diagram{root: nil, edges: nil, count: 0}
+	d.root = d.router("", doc.Router, methodOrder)
+
+	return d
+}
+
+// router adds the cluster of a router mounted on pattern, empty for the root router.
+func (d *diagram) router(pattern string, dr DocRouter, methodOrder []string) *diagramCluster {
+	label := pattern
+	if label == "" {
+		label = "/"
+	}
+
+	c := &diagramCluster{id: d.id("c"), label: label, nodes: nil, clusters: nil}
+	entry := d.node(c, routerNode, label)
+	last := d.chain(c, entry, dr.middlewares())
+
+	for _, pat := range dr.Routes.Patterns() {
+		rt := dr.Routes[pat]
+		full := JoinPattern(pattern, pat)
+
+		for _, meth := range rt.Handlers.Methods(methodOrder) {
+			dh := rt.Handlers[meth]
+			leaf := d.node(c, handlerNode, meth+" "+full, shortFuncName(dh.Func))
+			d.edges = append(d.edges, diagramEdge{from: d.chain(c, last, dh.Middlewares), to: leaf, label: ""})
+		}
+
+		if sr := rt.subRouter(); sr != nil {
+			sub := d.router(full, *sr, methodOrder)
+			c.clusters = append(c.clusters, sub)
+			d.edges = append(d.edges, diagramEdge{from: last, to: sub.nodes[0].id, label: pat})
+		}
+	}
+
+	return c
+}
+
+// chain adds the middleware nodes after the node from and returns the last one.
+func (d *diagram) chain(c *diagramCluster, from string, middlewares []DocMiddleware) string {
+	for _, mw := range middlewares {
+		id := d.node(c, middlewareNode, shortFuncName(mw.Func))
+		d.edges = append(d.edges, diagramEdge{from: from, to: id, label: ""})
+		from = id
+	}
+
+	return from
+}
+
+func (d *diagram) node(c *diagramCluster, kind diagramNodeKind, label ...string) string {
+	id := d.id("n")
+	c.nodes = append(c.nodes, diagramNode{id: id, label: label, kind: kind})
+
+	return id
+}
+
+func (d *diagram) id(prefix string) string {
+	d.count++
+
+	return fmt.Sprintf("%s%d", prefix, d.count)
+}
+
+// mermaid returns the Mermaid flowchart of the diagram.
+func (d *diagram) mermaid() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("flowchart LR\n")
+	d.root.mermaid(&buf, 1)
+
+	for _, e := range d.edges {
+		if e.label == "" {
+			fmt.Fprintf(&buf, "  %s --> %s\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(&buf, "  %s -->|%s| %s\n", e.from, mermaidText(e.label), e.to)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func (c *diagramCluster) mermaid(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Fprintf(buf, "%ssubgraph %s [%s]\n", indent, c.id, mermaidText(c.label))
+
+	for _, n := range c.nodes {
+		label := make([]string, len(n.label))
+		for i, line := range n.label {
+			label[i] = strings.Trim(mermaidText(line), `"`)
+		}
+		text := `"` + strings.Join(label, "<br/>") + `"`
+
+		switch n.kind {
+		case routerNode:
+			fmt.Fprintf(buf, "%s  %s([%s])\n", indent, n.id, text)
+		case middlewareNode:
+			fmt.Fprintf(buf, "%s  %s[[%s]]\n", indent, n.id, text)
+		case handlerNode:
+			fmt.Fprintf(buf, "%s  %s[%s]\n", indent, n.id, text)
+		}
+	}
+
+	for _, sub := range c.clusters {
+		sub.mermaid(buf, depth+1)
+	}
+
+	fmt.Fprintf(buf, "%send\n", indent)
+}
+
+// mermaidText quotes a label, escaping the characters of the Mermaid syntax.
+func mermaidText(s string) string {
+	s = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+
+	return `"` + s + `"`
+}
+
+// dot returns the Graphviz DOT graph of the diagram.
+func (d *diagram) dot() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph docgen {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	buf.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	d.root.dot(&buf, 1)
+
+	for _, e := range d.edges {
+		if e.label == "" {
+			fmt.Fprintf(&buf, "  %s -> %s;\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(&buf, "  %s -> %s [label=%s];\n", e.from, e.to, dotText(e.label))
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+func (c *diagramCluster) dot(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Fprintf(buf, "%ssubgraph cluster_%s {\n", indent, c.id)
+	fmt.Fprintf(buf, "%s  label=%s;\n", indent, dotText(c.label))
+
+	for _, n := range c.nodes {
+		label := dotText(strings.Join(n.label, "\n"))
+
+		switch n.kind {
+		case routerNode:
+			fmt.Fprintf(buf, "%s  %s [label=%s, shape=oval];\n", indent, n.id, label)
+		case middlewareNode:
+			fmt.Fprintf(buf, "%s  %s [label=%s, shape=box, style=rounded];\n", indent, n.id, label)
+		case handlerNode:
+			fmt.Fprintf(buf, "%s  %s [label=%s, shape=box];\n", indent, n.id, label)
+		}
+	}
+
+	for _, sub := range c.clusters {
+		sub.dot(buf, depth+1)
+	}
+
+	fmt.Fprintf(buf, "%s}\n", indent)
+}
+
+// dotText quotes a DOT string.
+func dotText(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// shortFuncName removes the import path of the package from a function name,
+// e.g. "github.com/go-chi/chi/v5/middleware.Logger" becomes "middleware.Logger".
+func shortFuncName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -0,0 +1,139 @@
+package docgen_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestDiagramGenerator(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		format string
+		want   string
+	}{{
+		format: docgen.DiagramMermaid,
+		want: `flowchart LR
+  subgraph c1 ["/"]
+    n2(["/"])
+    n9["GET /ping<br/>Ping"]
+    subgraph c3 ["/articles/*"]
+      n4(["/articles/*"])
+      n5[["Log"]]
+      n6["GET /articles/<br/>ListArticles"]
+      n7["POST /articles/<br/>CreateArticle"]
+      n8["GET /articles/{id}<br/>GetArticle"]
+    end
+  end
+  n4 --> n5
+  n5 --> n6
+  n5 --> n7
+  n5 --> n8
+  n2 -->|"/articles/*"| n4
+  n2 --> n9
+`,
+	}, {
+		format: docgen.DiagramDOT,
+		want: `digraph docgen {
+  rankdir=LR;
+  node [fontname="Helvetica", fontsize=10];
+  edge [fontname="Helvetica", fontsize=9];
+  subgraph cluster_c1 {
+    label="/";
+    n2 [label="/", shape=oval];
+    n9 [label="GET /ping\nPing", shape=box];
+    subgraph cluster_c3 {
+      label="/articles/*";
+      n4 [label="/articles/*", shape=oval];
+      n5 [label="Log", shape=box, style=rounded];
+      n6 [label="GET /articles/\nListArticles", shape=box];
+      n7 [label="POST /articles/\nCreateArticle", shape=box];
+      n8 [label="GET /articles/{id}\nGetArticle", shape=box];
+    }
+  }
+  n4 -> n5;
+  n5 -> n6;
+  n5 -> n7;
+  n5 -> n8;
+  n2 -> n4 [label="/articles/*"];
+  n2 -> n9;
+}
+`,
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.format, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := docgen.DiagramGenerator{Format: c.format, MethodOrder: nil}.GenerateDoc(oldDoc())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != c.want {
+				t.Errorf("GenerateDoc() =\n%s\nwant\n%s", b, c.want)
+			}
+		})
+	}
+}
+
+func TestDiagramGenerator_escape(t *testing.T) {
+	t.Parallel()
+
+	doc := docgen.Doc{Router: docgen.DocRouter{
+		Middlewares: []docgen.DocMiddleware{{FuncInfo: docgen.FuncInfo{Pkg: "", Func: "github.com/go-chi/chi/v5/middleware.Logger"}}},
+		Routes:      docgen.DocRoutes{`/say/{word:"[a-z]+"}`: {Handlers: docgen.DocHandlers{"GET": handler("GET", "Say")}}},
+	}}
+
+	mermaid, err := docgen.DiagramGenerator{}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["GET /say/{word:#quot;[a-z]+#quot;}<br/>Say"]`; !strings.Contains(string(mermaid), want) {
+		t.Errorf("Mermaid should contain %s, got:\n%s", want, mermaid)
+	}
+	if want := `[["middleware.Logger"]]`; !strings.Contains(string(mermaid), want) {
+		t.Errorf("Mermaid should contain %s, got:\n%s", want, mermaid)
+	}
+
+	dot, err := docgen.DiagramGenerator{Format: docgen.DiagramDOT}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[label="GET /say/{word:\"[a-z]+\"}\nSay", shape=box]`; !strings.Contains(string(dot), want) {
+		t.Errorf("DOT should contain %s, got:\n%s", want, dot)
+	}
+
+	if _, err := (docgen.DiagramGenerator{Format: "svg"}).GenerateDoc(doc); err == nil {
+		t.Error("GenerateDoc() should reject the svg format")
+	}
+}
+
+func TestDiagram_embedded(t *testing.T) {
+	t.Parallel()
+
+	md, err := docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{ProjectPath: "API", Diagram: true}}.GenerateDoc(oldDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# API\n\n\n\n## Diagram\n\n```mermaid\nflowchart LR\n"; !strings.Contains(string(md), want) {
+		t.Errorf("Markdown should contain %q, got:\n%s", want, md)
+	}
+
+	page, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Diagram: true, MermaidURL: "/static/mermaid.esm.min.mjs"}}.GenerateDoc(oldDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<pre class="mermaid">flowchart LR`,
+		`n6[&#34;GET /articles/&lt;br/&gt;ListArticles&#34;]`,
+		`import mermaid from "/static/mermaid.esm.min.mjs";`,
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("HTML should contain %s, got:\n%s", want, page)
+		}
+	}
+}
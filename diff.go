@@ -0,0 +1,301 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of a Change between two Docs.
+type ChangeKind string
+
+const (
+	RouteAdded         ChangeKind = "route-added"
+	ParamsChanged      ChangeKind = "params-changed"
+	RouteRemoved       ChangeKind = "route-removed"
+	MethodAdded        ChangeKind = "method-added"
+	MethodRemoved      ChangeKind = "method-removed"
+	HandlerChanged     ChangeKind = "handler-changed"
+	MiddlewaresChanged ChangeKind = "middlewares-changed"
+)
+
+// Change is a difference between two Docs, on a full route pattern.
+// Old and New hold the methods of an added or removed route,
+// the patterns of a route whose parameters changed, the handler function of a method or its chain of middlewares.
+type Change struct {
+	Kind    ChangeKind `json:"kind"`
+	Pattern string     `json:"pattern"`
+	Method  string     `json:"method,omitempty"`
+	Old     []string   `json:"old,omitempty"`
+	New     []string   `json:"new,omitempty"`
+}
+
+// DocDiff is the list of the changes between two Docs,
+// sorted by pattern, kind and method.
+type DocDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// route is a full route pattern of Doc.Endpoints: its endpoints by method.
+type route map[string]Endpoint
+
+// methods returns the methods of the route in DefaultMethodOrder.
+func (rt route) methods() []string {
+	handlers := make(DocHandlers, len(rt))
+	for method := range rt {
+		handlers[method] = DocHandler{}
+	}
+
+	return handlers.Methods(nil)
+}
+
+// Diff compares the routes of two Docs, generated from two versions of a router,
+// flattening their DocRouter trees into full route patterns.
+// A removed route and an added route differing only by the names or the regexps
+// of their parameters are reported as a ParamsChanged route.
+// The handlers and the middlewares are identified by their package and function names.
+func Diff(old, new Doc) DocDiff {
+	oldRoutes := flattenRoutes(old)
+	newRoutes := flattenRoutes(new)
+
+	diff := DocDiff{Changes: []Change{}}
+
+	renamed := matchParams(oldRoutes, newRoutes)
+	previous := make(map[string]bool, len(renamed))
+	for _, pat := range renamed {
+		previous[pat] = true
+	}
+
+	for pat, ort := range oldRoutes {
+		if _, ok := newRoutes[pat]; !ok && !previous[pat] {
+			diff.add(RouteRemoved, pat, "", ort.methods(), nil)
+		}
+	}
+
+	for pat, nrt := range newRoutes {
+		oldPat := pat
+		if o, ok := renamed[pat]; ok {
+			diff.add(ParamsChanged, pat, "", []string{o}, []string{pat})
+			oldPat = o
+		}
+
+		ort, ok := oldRoutes[oldPat]
+		if !ok {
+			diff.add(RouteAdded, pat, "", nil, nrt.methods())
+
+			continue
+		}
+
+		diff.compareHandlers(pat, ort, nrt)
+	}
+
+	diff.sort()
+
+	return diff
+}
+
+// Empty reports whether the Docs have the same routes.
+func (d DocDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// JSON returns the indented JSON report of the changes.
+func (d DocDiff) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: json.MarshalIndent: %w", err)
+	}
+
+	return b, nil
+}
+
+// Markdown returns the report of the changes as a Markdown table.
+func (d DocDiff) Markdown() []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString("# API changes\n\n")
+
+	if d.Empty() {
+		buf.WriteString("No changes.\n")
+
+		return buf.Bytes()
+	}
+
+	writeChanges(&buf, d.Changes)
+
+	return buf.Bytes()
+}
+
+// writeChanges writes the changes as a Markdown table.
+func writeChanges(buf *bytes.Buffer, changes []Change) {
+	buf.WriteString("| Change | Route | Method | Old | New |\n")
+	buf.WriteString("|--------|-------|--------|-----|-----|\n")
+
+	for _, c := range changes {
+		fmt.Fprintf(buf, "| %s | `%s` | %s | %s | %s |\n",
+			c.Kind, cell(c.Pattern), c.Method, codeList(c.Old), codeList(c.New))
+	}
+}
+
+// compareHandlers adds the changes of the methods of a route.
+func (d *DocDiff) compareHandlers(pattern string, ort, nrt route) {
+	for method := range ort {
+		if _, ok := nrt[method]; !ok {
+			d.add(MethodRemoved, pattern, method, nil, nil)
+		}
+	}
+
+	for method, nep := range nrt {
+		oep, ok := ort[method]
+		if !ok {
+			d.add(MethodAdded, pattern, method, nil, nil)
+
+			continue
+		}
+
+		if o, n := funcName(oep.Handler), funcName(nep.Handler); o != n {
+			d.add(HandlerChanged, pattern, method, []string{o}, []string{n})
+		}
+
+		o := middlewareNames(oep.EffectiveMiddlewares)
+		n := middlewareNames(nep.EffectiveMiddlewares)
+		if strings.Join(o, "\n") != strings.Join(n, "\n") {
+			d.add(MiddlewaresChanged, pattern, method, o, n)
+		}
+	}
+}
+
+func (d *DocDiff) add(kind ChangeKind, pattern, method string, oldNames, newNames []string) {
+	d.Changes = append(d.Changes, Change{
+		Kind:    kind,
+		Pattern: pattern,
+		Method:  method,
+		Old:     oldNames,
+		New:     newNames,
+	})
+}
+
+var kindOrder = map[ChangeKind]int{
+	RouteRemoved:       0,
+	RouteAdded:         1,
+	ParamsChanged:      2,
+	MethodRemoved:      3,
+	MethodAdded:        4,
+	HandlerChanged:     5,
+	MiddlewaresChanged: 6,
+}
+
+func (d *DocDiff) sort() {
+	sort.Slice(d.Changes, func(i, j int) bool {
+		ci, cj := d.Changes[i], d.Changes[j]
+		if ci.Pattern != cj.Pattern {
+			return ci.Pattern < cj.Pattern
+		}
+		if ci.Kind != cj.Kind {
+			return kindOrder[ci.Kind] < kindOrder[cj.Kind]
+		}
+
+		return ci.Method < cj.Method
+	})
+}
+
+// flattenRoutes groups the Endpoints of the Doc by full pattern.
+func flattenRoutes(d Doc) map[string]route {
+	routes := map[string]route{}
+
+	for _, ep := range d.Endpoints() {
+		if routes[ep.FullPattern] == nil {
+			routes[ep.FullPattern] = route{}
+		}
+		routes[ep.FullPattern][ep.Method] = ep
+	}
+
+	return routes
+}
+
+// matchParams returns the old pattern of the new routes whose parameters
+// have been renamed or constrained by another regexp, by new pattern.
+func matchParams(oldRoutes, newRoutes map[string]route) map[string]string {
+	removed := map[string]string{} // shape : old pattern
+	for pat := range oldRoutes {
+		if _, ok := newRoutes[pat]; ok {
+			continue
+		}
+
+		shape := patternShape(pat)
+		if o, ok := removed[shape]; !ok || pat < o {
+			removed[shape] = pat
+		}
+	}
+
+	added := make([]string, 0, len(newRoutes))
+	for pat := range newRoutes {
+		if _, ok := oldRoutes[pat]; !ok {
+			added = append(added, pat)
+		}
+	}
+	sort.Strings(added)
+
+	renamed := map[string]string{}
+	for _, pat := range added {
+		shape := patternShape(pat)
+		if o, ok := removed[shape]; ok {
+			renamed[pat] = o
+			delete(removed, shape)
+		}
+	}
+
+	return renamed
+}
+
+// patternShape replaces the named parameters of a route pattern by "{}".
+func patternShape(pattern string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '{' {
+			b.WriteString("{}")
+			i = closingBrace(pattern, i)
+		} else {
+			b.WriteByte(pattern[i])
+		}
+	}
+
+	return b.String()
+}
+
+// funcName returns the qualified name of a function.
+func funcName(fi FuncInfo) string {
+	if fi.Pkg == "" {
+		return fi.Func
+	}
+
+	return fi.Pkg + "." + fi.Func
+}
+
+// middlewareNames returns the names of the middlewares.
+func middlewareNames(middlewares []DocMiddleware) []string {
+	names := make([]string, len(middlewares))
+	for i, mw := range middlewares {
+		names[i] = funcName(mw.FuncInfo)
+	}
+
+	return names
+}
+
+// cell escapes the pipes of a Markdown table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// codeList formats names as a comma-separated list of code spans.
+func codeList(names []string) string {
+	items := make([]string, len(names))
+	for i, name := range names {
+		items[i] = "`" + cell(name) + "`"
+	}
+
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,125 @@
+package docgen_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func handler(method, fn string, middlewares ...string) docgen.DocHandler {
+	dh := docgen.DocHandler{
+		Middlewares: []docgen.DocMiddleware{},
+		Method:      method,
+		Params:      nil,
+		FuncInfo:    docgen.FuncInfo{Pkg: "example.com/api", Func: fn},
+	}
+	for _, mw := range middlewares {
+		dh.Middlewares = append(dh.Middlewares, docgen.DocMiddleware{FuncInfo: docgen.FuncInfo{Pkg: "example.com/api", Func: mw}})
+	}
+
+	return dh
+}
+
+func oldDoc() docgen.Doc {
+	return docgen.Doc{
+		Router: docgen.DocRouter{
+			Middlewares: []docgen.DocMiddleware{},
+			Routes: docgen.DocRoutes{
+				"/ping": {Handlers: docgen.DocHandlers{"GET": handler("GET", "Ping")}},
+				"/articles/*": {Router: &docgen.DocRouter{
+					Middlewares: []docgen.DocMiddleware{{FuncInfo: docgen.FuncInfo{Pkg: "example.com/api", Func: "Log"}}},
+					Routes: docgen.DocRoutes{
+						"/": {Handlers: docgen.DocHandlers{
+							"GET":  handler("GET", "ListArticles"),
+							"POST": handler("POST", "CreateArticle"),
+						}},
+						"/{id}": {Handlers: docgen.DocHandlers{"GET": handler("GET", "GetArticle")}},
+					},
+				}},
+			},
+		},
+		Schemas: nil,
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	newDoc := oldDoc()
+	delete(newDoc.Router.Routes, "/ping")
+	newDoc.Router.Routes["/health"] = docgen.DocRoute{Handlers: docgen.DocHandlers{"GET": handler("GET", "Health")}}
+	articles := newDoc.Router.Routes["/articles/*"].Router
+	articles.Routes["/"] = docgen.DocRoute{Handlers: docgen.DocHandlers{
+		"GET":    handler("GET", "ListArticles", "Paginate"),
+		"DELETE": handler("DELETE", "DeleteArticles"),
+	}}
+	articles.Routes["/{id}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{"GET": handler("GET", "ShowArticle")}}
+
+	want := []docgen.Change{{
+		Kind:    docgen.MethodRemoved,
+		Pattern: "/articles/",
+		Method:  "POST",
+	}, {
+		Kind:    docgen.MethodAdded,
+		Pattern: "/articles/",
+		Method:  "DELETE",
+	}, {
+		Kind:    docgen.MiddlewaresChanged,
+		Pattern: "/articles/",
+		Method:  "GET",
+		Old:     []string{"example.com/api.Log"},
+		New:     []string{"example.com/api.Log", "example.com/api.Paginate"},
+	}, {
+		Kind:    docgen.HandlerChanged,
+		Pattern: "/articles/{id}",
+		Method:  "GET",
+		Old:     []string{"example.com/api.GetArticle"},
+		New:     []string{"example.com/api.ShowArticle"},
+	}, {
+		Kind:    docgen.RouteAdded,
+		Pattern: "/health",
+		New:     []string{"GET"},
+	}, {
+		Kind:    docgen.RouteRemoved,
+		Pattern: "/ping",
+		Old:     []string{"GET"},
+	}}
+
+	got := docgen.Diff(oldDoc(), newDoc)
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("Diff() = %+v\nwant %+v", got.Changes, want)
+	}
+
+	md := string(got.Markdown())
+	for _, line := range []string{
+		"| handler-changed | `/articles/{id}` | GET | `example.com/api.GetArticle` | `example.com/api.ShowArticle` |\n",
+		"| route-added | `/health` |  |  | `GET` |\n",
+	} {
+		if !strings.Contains(md, line) {
+			t.Errorf("Markdown() should contain %q, got:\n%s", line, md)
+		}
+	}
+
+	b, err := got.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"kind": "middlewares-changed"`) {
+		t.Errorf("JSON() = %s", b)
+	}
+}
+
+func TestDiff_same(t *testing.T) {
+	t.Parallel()
+
+	d := docgen.Diff(oldDoc(), oldDoc())
+	if !d.Empty() {
+		t.Errorf("Diff() = %+v, want no change", d.Changes)
+	}
+
+	if md := string(d.Markdown()); !strings.Contains(md, "No changes.") {
+		t.Errorf("Markdown() = %s", md)
+	}
+}
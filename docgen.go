@@ -3,6 +3,7 @@ package docgen
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -10,12 +11,26 @@ import (
 )
 
 type Doc struct {
-	Router DocRouter `json:"router"`
+	Router  DocRouter          `json:"router"`
+	Schemas map[string]*Schema `json:"schemas,omitempty"` // set by ResolveSchemas
 }
 
 type DocRouter struct {
-	Middlewares []DocMiddleware `json:"middlewares"`
-	Routes      DocRoutes       `json:"routes"`
+	// InlineMiddlewares are the middlewares of the mount point of a sub-router,
+	// set by With before Route or Mount, executed before the Middlewares.
+	InlineMiddlewares []DocMiddleware `json:"inline_middlewares,omitempty"`
+	Middlewares       []DocMiddleware `json:"middlewares"`
+	Routes            DocRoutes       `json:"routes"`
+}
+
+// middlewares returns the middlewares executed by the router before its routes:
+// the inline ones of its mount point, then its own.
+func (dr DocRouter) middlewares() []DocMiddleware {
+	if len(dr.InlineMiddlewares) == 0 {
+		return dr.Middlewares
+	}
+
+	return append(dr.InlineMiddlewares[:len(dr.InlineMiddlewares):len(dr.InlineMiddlewares)], dr.Middlewares...)
 }
 
 type DocMiddleware struct {
@@ -24,46 +39,83 @@ type DocMiddleware struct {
 
 type DocRoute struct {
 	Pattern  string      `json:"-"`
+	Params   []DocParam  `json:"params,omitempty"` // Params of Pattern only
 	Handlers DocHandlers `json:"handlers,omitempty"`
 	Router   *DocRouter  `json:"router,omitempty"`
 }
 
+// subRouter returns the router mounted on the route by Route or Mount,
+// nil for a route with handlers, whose Router is empty.
+func (rt DocRoute) subRouter() *DocRouter {
+	if len(rt.Handlers) > 0 {
+		return nil
+	}
+
+	return rt.Router
+}
+
 type DocRoutes map[string]DocRoute // Pattern : DocRoute
 
+// UnmarshalJSON restores the Pattern of the routes from their keys.
+func (drs *DocRoutes) UnmarshalJSON(b []byte) error {
+	var routes map[string]DocRoute
+	if err := json.Unmarshal(b, &routes); err != nil {
+		return err
+	}
+
+	*drs = make(DocRoutes, len(routes))
+	for pattern, drt := range routes {
+		drt.Pattern = pattern
+		(*drs)[pattern] = drt
+	}
+
+	return nil
+}
+
 type DocHandler struct {
 	Middlewares []DocMiddleware `json:"middlewares"`
 	Method      string          `json:"method"`
+	Params      []DocParam      `json:"params,omitempty"` // Params of the full route pattern
 	FuncInfo
 }
 
 type DocHandlers map[string]DocHandler // Method : DocHandler
 
+// PrintRoutes prints the full patterns of the routes of r to os.Stdout, one per line,
+// in the order of chi. Use RoutePrinter for the methods, the handlers, the middlewares or a tree.
 func PrintRoutes(r chi.Routes) {
-	var printRoutes func(parentPattern string, r chi.Routes)
-	printRoutes = func(parentPattern string, r chi.Routes) {
-		rts := r.Routes()
-		for _, rt := range rts {
-			if rt.SubRoutes == nil {
-				fmt.Println(parentPattern + rt.Pattern)
-			} else {
-				pat := rt.Pattern
-				subRoutes := rt.SubRoutes
-				printRoutes(parentPattern+pat, subRoutes)
-			}
-		}
+	if err := (RoutePrinter{}).Print(r); err != nil {
+		log.Print(err)
 	}
-	printRoutes("", r)
 }
 
 func JSONRoutesDoc(r chi.Routes) string {
 	return string(JSONRoutesBytes(r))
 }
 
+// JSONRoutesBytes returns the indented JSON of the Doc, or nil on error.
+// Use JSONGenerator to get the error.
 func JSONRoutesBytes(r chi.Routes) []byte {
-	doc, _ := BuildDoc(r)
-	b, err := json.MarshalIndent(doc, "", "  ")
+	b, err := JSONGenerator{}.Generate(r)
 	if err != nil {
-		log.Panicf("docgen: json.MarshalIndent err: %q input: %+v", err, doc)
+		log.Print(err)
 	}
+
 	return b
 }
+
+// ParseJSON loads a Doc from the JSON generated by JSONRoutesBytes or JSONGenerator,
+// such as a snapshot of a running service, to generate the other formats offline.
+func ParseJSON(b []byte) (Doc, error) {
+	var doc Doc
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return doc, fmt.Errorf("docgen: json.Unmarshal: %w", err)
+	}
+
+	if doc.Router.Routes == nil {
+		return doc, errors.New("docgen: not the JSON of a docgen.Doc")
+	}
+
+	return doc, nil
+}
@@ -1,6 +1,7 @@
 package docgen_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -201,9 +202,56 @@ func TestJSONRoutesDoc(t *testing.T) {
 		name string
 		r    chi.Routes
 		want string
-	}{
-		// TODO: Add test cases.
-	}
+	}{{
+		name: "empty router of a route with handlers",
+		r: func() chi.Routes {
+			r := chi.NewRouter()
+			r.Get("/hubs", hubIndexHandler)
+
+			return r
+		}(),
+		want: `{
+  "router": {
+    "middlewares": [],
+    "routes": {
+      "/hubs": {
+        "handlers": {
+          "GET": {
+            "middlewares": [],
+            "method": "GET",
+            "pkg": "",
+            "func": "github.com/teal-finance/docgen-yes_test.hubIndexHandler",
+            "comment": "",
+            "file": "github.com/teal-finance/docgen-yes/docgen_test.go",
+            "line": 23,
+            "unresolvable": true
+          }
+        },
+        "router": {
+          "middlewares": [],
+          "routes": {}
+        }
+      }
+    }
+  },
+  "endpoints": [
+    {
+      "full_pattern": "/hubs",
+      "method": "GET",
+      "handler": {
+        "pkg": "",
+        "func": "github.com/teal-finance/docgen-yes_test.hubIndexHandler",
+        "comment": "",
+        "file": "github.com/teal-finance/docgen-yes/docgen_test.go",
+        "line": 23,
+        "unresolvable": true
+      },
+      "effective_middlewares": [],
+      "mount_path": "/"
+    }
+  ]
+}`,
+	}}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -213,3 +261,44 @@ func TestJSONRoutesDoc(t *testing.T) {
 		})
 	}
 }
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	doc, err := docgen.BuildDoc(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := docgen.JSONGenerator{}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := docgen.ParseJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for pat, rt := range parsed.Router.Routes {
+		if rt.Pattern != pat {
+			t.Errorf("Pattern = %q, want %q", rt.Pattern, pat)
+		}
+	}
+
+	want, err := docgen.MarkdownGenerator{}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := docgen.MarkdownGenerator{}.GenerateDoc(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Markdown of the parsed Doc:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := docgen.ParseJSON([]byte(`{"routes": {}}`)); err == nil {
+		t.Error("ParseJSON() should reject a JSON without router")
+	}
+}
@@ -0,0 +1,82 @@
+package docgen
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Endpoint is a method of a route with the effective middlewares
+// of its handler, see Doc.Endpoints.
+type Endpoint struct {
+	FullPattern string   `json:"full_pattern"` // e.g. "/articles/{articleID}"
+	Method      string   `json:"method"`
+	Handler     FuncInfo `json:"handler"`
+
+	// EffectiveMiddlewares are the middlewares of the routers from the root,
+	// each after the inline ones of its mount point,
+	// then the inline ones of the handler, in execution order.
+	EffectiveMiddlewares []DocMiddleware `json:"effective_middlewares"`
+
+	// MountPath is the full pattern of the router of the route,
+	// without the "/*" of its mount point: "/" for the root router.
+	MountPath string `json:"mount_path"`
+}
+
+// Endpoints flattens the router tree into the list of its endpoints,
+// sorted by full pattern, then by DefaultMethodOrder.
+// The routes without handler nor sub-router are skipped.
+func (d Doc) Endpoints() []Endpoint {
+	endpoints := []Endpoint{}
+	mounts := []string{} // patterns of the routers from the root
+
+	_ = Walk(d, VisitorFuncs{ // the funcs return no error
+		EnterRouterFunc: func(s WalkState, _ DocRouter) error {
+			mounts = append(mounts, s.Pattern)
+
+			return nil
+		},
+		LeaveRouterFunc: func(WalkState, DocRouter) error {
+			mounts = mounts[:len(mounts)-1]
+
+			return nil
+		},
+		VisitRouteFunc: nil,
+		VisitHandlerFunc: func(s WalkState, dh DocHandler) error {
+			mountPath := strings.TrimSuffix(mounts[len(mounts)-1], "/*")
+			if mountPath == "" {
+				mountPath = "/"
+			}
+
+			endpoints = append(endpoints, Endpoint{
+				FullPattern:          s.Pattern,
+				Method:               dh.Method,
+				Handler:              dh.FuncInfo,
+				EffectiveMiddlewares: append([]DocMiddleware{}, s.Middlewares...),
+				MountPath:            mountPath,
+			})
+
+			return nil
+		},
+		VisitMiddlewareFunc: nil,
+	})
+
+	// the handlers of a sub-router are listed after the routes of its parent
+	sort.SliceStable(endpoints, func(i, j int) bool { return endpoints[i].FullPattern < endpoints[j].FullPattern })
+
+	return endpoints
+}
+
+// MarshalJSON adds the Endpoints to the JSON of the Doc,
+// ignored by ParseJSON as they are rebuilt from the router tree.
+func (d Doc) MarshalJSON() ([]byte, error) {
+	type doc Doc // without the MarshalJSON method
+
+	return json.Marshal(struct {
+		doc
+		Endpoints []Endpoint `json:"endpoints"`
+	}{
+		doc:       doc(d),
+		Endpoints: d.Endpoints(),
+	})
+}
@@ -0,0 +1,137 @@
+package docgen_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestDoc_Endpoints(t *testing.T) {
+	t.Parallel()
+
+	doc := oldDoc()
+	articles := doc.Router.Routes["/articles/*"].Router
+	articles.Routes["/{id}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{
+		"DELETE": handler("DELETE", "DeleteArticle", "Auth"),
+		"GET":    handler("GET", "GetArticle"),
+	}}
+	articles.Routes["/empty"] = docgen.DocRoute{} // skipped
+
+	log := docgen.DocMiddleware{FuncInfo: docgen.FuncInfo{Pkg: "example.com/api", Func: "Log"}}
+	auth := docgen.DocMiddleware{FuncInfo: docgen.FuncInfo{Pkg: "example.com/api", Func: "Auth"}}
+
+	type endpoint struct {
+		pattern     string
+		method      string
+		handler     string
+		middlewares []docgen.DocMiddleware
+		mountPath   string
+	}
+
+	want := []endpoint{
+		{pattern: "/articles/", method: "GET", handler: "ListArticles", middlewares: []docgen.DocMiddleware{log}, mountPath: "/articles"},
+		{pattern: "/articles/", method: "POST", handler: "CreateArticle", middlewares: []docgen.DocMiddleware{log}, mountPath: "/articles"},
+		{pattern: "/articles/{id}", method: "GET", handler: "GetArticle", middlewares: []docgen.DocMiddleware{log}, mountPath: "/articles"},
+		{pattern: "/articles/{id}", method: "DELETE", handler: "DeleteArticle", middlewares: []docgen.DocMiddleware{log, auth}, mountPath: "/articles"},
+		{pattern: "/ping", method: "GET", handler: "Ping", middlewares: []docgen.DocMiddleware{}, mountPath: "/"},
+	}
+
+	got := doc.Endpoints()
+	if len(got) != len(want) {
+		t.Fatalf("Endpoints() = %+v, want %d endpoints", got, len(want))
+	}
+
+	for i, ep := range got {
+		w := want[i]
+		if ep.FullPattern != w.pattern || ep.Method != w.method || ep.Handler.Func != w.handler || ep.MountPath != w.mountPath {
+			t.Errorf("Endpoints()[%d] = %s %s %s (mount %s), want %s %s %s (mount %s)",
+				i, ep.Method, ep.FullPattern, ep.Handler.Func, ep.MountPath, w.method, w.pattern, w.handler, w.mountPath)
+		}
+		if !reflect.DeepEqual(ep.EffectiveMiddlewares, w.middlewares) {
+			t.Errorf("Endpoints()[%d].EffectiveMiddlewares = %+v, want %+v", i, ep.EffectiveMiddlewares, w.middlewares)
+		}
+	}
+
+	// the generators skip the empty route too
+	if _, err := (docgen.MarkdownGenerator{}).GenerateDoc(doc); err != nil {
+		t.Errorf("MarkdownGenerator.GenerateDoc() error = %v", err)
+	}
+	if _, err := (docgen.MarkupGenerator{}).GenerateDoc(doc); err != nil {
+		t.Errorf("MarkupGenerator.GenerateDoc() error = %v", err)
+	}
+}
+
+func TestDoc_Endpoints_inlineMount(t *testing.T) {
+	t.Parallel()
+
+	sub := chi.NewRouter()
+	sub.Get("/{hubID}", hubIndexHandler)
+
+	r := chi.NewRouter()
+	r.With(Auth).Route("/admin", func(r chi.Router) {
+		r.Use(RequestID)
+		r.Get("/{hubID}", hubIndexHandler)
+	})
+	r.With(Auth).Mount("/hubs", sub)
+
+	doc, err := docgen.BuildDoc(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"/admin/{hubID}": {"Auth", "RequestID"},
+		"/hubs/{hubID}":  {"Auth"},
+	}
+
+	endpoints := doc.Endpoints()
+	if len(endpoints) != len(want) {
+		t.Fatalf("Endpoints() = %+v, want %d endpoints", endpoints, len(want))
+	}
+
+	for _, ep := range endpoints {
+		got := make([]string, len(ep.EffectiveMiddlewares))
+		for i, mw := range ep.EffectiveMiddlewares {
+			got[i] = strings.TrimPrefix(mw.Func, testPkg+".")
+		}
+		if !reflect.DeepEqual(got, want[ep.FullPattern]) {
+			t.Errorf("EffectiveMiddlewares of %s = %q, want %q", ep.FullPattern, got, want[ep.FullPattern])
+		}
+	}
+}
+
+func TestDoc_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	b, err := docgen.JSONGenerator{}.GenerateDoc(oldDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw struct {
+		Router    json.RawMessage   `json:"router"`
+		Endpoints []docgen.Endpoint `json:"endpoints"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Router) == 0 || len(raw.Endpoints) != 4 {
+		t.Errorf("JSON = %s", b)
+	}
+	if !strings.Contains(string(b), `"full_pattern": "/articles/{id}",`) {
+		t.Errorf("JSON should contain the full patterns, got:\n%s", b)
+	}
+
+	doc, err := docgen.ParseJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.Endpoints(), raw.Endpoints) {
+		t.Errorf("Endpoints() of the parsed Doc = %+v, want %+v", doc.Endpoints(), raw.Endpoints)
+	}
+}
@@ -0,0 +1,216 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Explanation is what serves a request, see Explain.
+type Explanation struct {
+	Method string `json:"method"`
+	Path   string `json:"path"` // routing path of the URL, without the query
+
+	// FullPattern is the matched route pattern, e.g. "/articles/{articleID}",
+	// and RoutePatterns the patterns matched by each router, as chi.Context.RoutePatterns
+	// but with the mount pattern "/articles/*" instead of its stubs "/articles" and "/articles/".
+	FullPattern   string   `json:"full_pattern"`
+	RoutePatterns []string `json:"route_patterns"`
+
+	// URLParams are the URL parameters extracted by chi, in order,
+	// including the "*" of the mount points.
+	URLParams []URLParam `json:"url_params"`
+
+	// Middlewares is the complete middleware chain, in execution order: the middlewares
+	// of each router from the root, preceded by the inline ones of its mount point,
+	// then the inline ones of the handler.
+	Middlewares []Layer `json:"middlewares"`
+
+	Handler FuncInfo `json:"handler"`
+}
+
+// URLParam is a URL parameter of an Explanation.
+type URLParam struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Layer is a middleware of the chain of an Explanation.
+type Layer struct {
+	FuncInfo
+
+	// Pattern is the full pattern of the router of the middleware, without the "/*"
+	// of its mount point and "/" for the root router,
+	// or the full pattern of the route for an inline middleware.
+	Pattern string `json:"pattern"`
+	Inline  bool   `json:"inline"`
+}
+
+// Explain returns the route, the URL parameters, the middlewares and the handler
+// serving the request method and URL with the router r, using the matching of chi.
+// The URL is a path with an optional query, or an absolute URL.
+func Explain(r chi.Routes, method, rawURL string) (Explanation, error) {
+	method = strings.ToUpper(method)
+
+	e := Explanation{
+		Method:        method,
+		Path:          "",
+		FullPattern:   "",
+		RoutePatterns: []string{},
+		URLParams:     []URLParam{},
+		Middlewares:   []Layer{},
+		Handler:       FuncInfo{},
+	}
+
+	if r == nil {
+		return e, fmt.Errorf("docgen: explain %s %s: router is nil", method, rawURL)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return e, fmt.Errorf("docgen: explain %s %s: %w", method, rawURL, err)
+	}
+
+	// same routing path as chi.Mux.routeHTTP
+	e.Path = u.RawPath
+	if e.Path == "" {
+		e.Path = u.Path
+	}
+	if e.Path == "" {
+		e.Path = "/"
+	}
+
+	rs := &Resolver{KeepAST: false, Workers: 0}
+
+	rctx := chi.NewRouteContext()
+	if !r.Match(rctx, method, e.Path) {
+		return e, fmt.Errorf("docgen: explain %s %s: no route matches", method, e.Path)
+	}
+
+	// find the matched route of each router to collect the middlewares
+	for i := 0; i < len(rctx.RoutePatterns); i++ {
+		mountPath := strings.TrimSuffix(e.FullPattern, "/*")
+		if mountPath == "" {
+			mountPath = "/"
+		}
+		for _, mw := range r.Middlewares() {
+			e.Middlewares = append(e.Middlewares, Layer{FuncInfo: rs.FuncInfo(mw), Pattern: mountPath, Inline: false})
+		}
+
+		rt, ok := findRoute(r, rctx.RoutePatterns[i])
+		if !ok {
+			// chi matches "/articles" and "/articles/" on stubs of the mount point "/articles/*",
+			// not listed by Routes, and the sub-router is not matched until served
+			rt, ok = findRoute(r, strings.TrimSuffix(rctx.RoutePatterns[i], "/")+"/*")
+			if !ok {
+				return e, fmt.Errorf("docgen: explain %s %s: route %s not found", method, e.Path, rctx.RoutePatterns[i])
+			}
+			rctx.RoutePatterns[i] = rt.Pattern
+
+			if rt.SubRoutes != nil && i == len(rctx.RoutePatterns)-1 && !rt.SubRoutes.Match(rctx, method, "/") {
+				return e, fmt.Errorf("docgen: explain %s %s: no route matches", method, e.Path)
+			}
+		}
+
+		e.FullPattern = JoinPattern(e.FullPattern, rt.Pattern)
+
+		if rt.SubRoutes != nil && i < len(rctx.RoutePatterns)-1 {
+			// inline middlewares of the mount point, e.g. r.With(Auth).Route("/admin", ...)
+			if chain, ok := rt.Handlers["*"].(*chi.ChainHandler); ok {
+				for _, mw := range chain.Middlewares {
+					e.Middlewares = append(e.Middlewares, Layer{FuncInfo: rs.FuncInfo(mw), Pattern: e.FullPattern, Inline: true})
+				}
+			}
+			r = rt.SubRoutes
+
+			continue
+		}
+
+		h := rt.Handlers[method]
+		if h == nil {
+			h = rt.Handlers["*"]
+		}
+
+		if chain, ok := h.(*chi.ChainHandler); ok {
+			for _, mw := range chain.Middlewares {
+				e.Middlewares = append(e.Middlewares, Layer{FuncInfo: rs.FuncInfo(mw), Pattern: e.FullPattern, Inline: true})
+			}
+			h = chain.Endpoint
+		}
+
+		e.Handler = rs.FuncInfo(h)
+	}
+
+	e.RoutePatterns = append(e.RoutePatterns, rctx.RoutePatterns...)
+	for i, key := range rctx.URLParams.Keys {
+		e.URLParams = append(e.URLParams, URLParam{Key: key, Value: rctx.URLParams.Values[i]})
+	}
+
+	return e, nil
+}
+
+// findRoute returns the route of the router r having the pattern.
+func findRoute(r chi.Routes, pattern string) (chi.Route, bool) {
+	for _, rt := range r.Routes() {
+		if rt.Pattern == pattern {
+			return rt, true
+		}
+	}
+
+	return chi.Route{SubRoutes: nil, Handlers: map[string]http.Handler{}, Pattern: ""}, false
+}
+
+// Text returns the Explanation as aligned lines of text:
+//
+//	GET /articles/42
+//	pattern     /articles/{articleID}
+//	param       articleID = 42
+//	middleware  example.com/api.Log  api/log.go:12  (router /)
+//	handler     example.com/api.GetArticle  api/articles.go:30
+func (e Explanation) Text() []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "%s %s\n", e.Method, e.Path)
+	fmt.Fprintf(&buf, "%-12s%s\n", "pattern", e.FullPattern)
+
+	for _, p := range e.URLParams {
+		fmt.Fprintf(&buf, "%-12s%s = %s\n", "param", p.Key, p.Value)
+	}
+
+	for _, mw := range e.Middlewares {
+		where := "router " + mw.Pattern
+		if mw.Inline {
+			where = "inline"
+		}
+		fmt.Fprintf(&buf, "%-12s%s  (%s)\n", "middleware", funcText(mw.FuncInfo), where)
+	}
+
+	fmt.Fprintf(&buf, "%-12s%s\n", "handler", funcText(e.Handler))
+
+	return buf.Bytes()
+}
+
+// JSON returns the indented JSON of the Explanation.
+func (e Explanation) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: json.MarshalIndent: %w", err)
+	}
+
+	return b, nil
+}
+
+// funcText returns the qualified name of the function and its position.
+func funcText(fi FuncInfo) string {
+	name := funcName(fi)
+	if fi.File == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s  %s:%d", name, fi.File, fi.Line)
+}
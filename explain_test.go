@@ -0,0 +1,248 @@
+package docgen_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// testPkg prefixes the functions of the test package in FuncInfo.Func.
+const testPkg = "github.com/teal-finance/docgen-yes_test"
+
+// hubsRouter mounts a sub-router with router and inline middlewares.
+func hubsRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RequestID)
+	r.Route("/hubs", func(r chi.Router) {
+		r.Use(Auth)
+		r.With(RequestID).Get("/{hubID}", hubIndexHandler)
+	})
+
+	return r
+}
+
+// Auth comment goes here.
+func Auth(next http.Handler) http.Handler {
+	return next
+}
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	e, err := docgen.Explain(hubsRouter(), "get", "/hubs/42?view=full")
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+
+	if e.Method != "GET" || e.Path != "/hubs/42" || e.FullPattern != "/hubs/{hubID}" {
+		t.Errorf("Explain() = %s %s %s, want GET /hubs/42 /hubs/{hubID}", e.Method, e.Path, e.FullPattern)
+	}
+	if want := []string{"/hubs/*", "/{hubID}"}; !reflect.DeepEqual(e.RoutePatterns, want) {
+		t.Errorf("RoutePatterns = %q, want %q", e.RoutePatterns, want)
+	}
+	if want := []docgen.URLParam{{Key: "*", Value: "42"}, {Key: "hubID", Value: "42"}}; !reflect.DeepEqual(e.URLParams, want) {
+		t.Errorf("URLParams = %+v, want %+v", e.URLParams, want)
+	}
+
+	type layer struct {
+		fn      string
+		pattern string
+		inline  bool
+	}
+	want := []layer{{"RequestID", "/", false}, {"Auth", "/hubs", false}, {"RequestID", "/hubs/{hubID}", true}}
+	got := make([]layer, len(e.Middlewares))
+	for i, mw := range e.Middlewares {
+		got[i] = layer{strings.TrimPrefix(mw.Func, testPkg+"."), mw.Pattern, mw.Inline}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Middlewares = %+v, want %+v", got, want)
+	}
+
+	if e.Handler.Func != testPkg+".hubIndexHandler" || e.Handler.Line == 0 {
+		t.Errorf("Handler = %+v, want hubIndexHandler", e.Handler)
+	}
+
+	text := string(e.Text())
+	for _, line := range []string{
+		"GET /hubs/42\n",
+		"pattern     /hubs/{hubID}\n",
+		"param       hubID = 42\n",
+		"(router /hubs)\n",
+		"(inline)\n",
+		"handler     github.com/teal-finance/docgen-yes_test.hubIndexHandler  ",
+	} {
+		if !strings.Contains(text, line) {
+			t.Errorf("Text() should contain %q, got:\n%s", line, text)
+		}
+	}
+}
+
+func TestExplain_error(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{name: "no route", method: "GET", url: "/users", want: "docgen: explain GET /users: no route matches"},
+		{name: "method", method: "POST", url: "/hubs/42", want: "docgen: explain POST /hubs/42: no route matches"},
+		{name: "unknown method", method: "BREW", url: "/hubs/42", want: "no route matches"},
+		{name: "invalid url", method: "GET", url: "%", want: "invalid URL escape"},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := docgen.Explain(hubsRouter(), c.method, c.url)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("Explain() error = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestHandler_explain(t *testing.T) {
+	t.Parallel()
+
+	r := hubsRouter()
+	h := docgen.Handler(r, docgen.HandlerOpts{})
+
+	cases := []struct {
+		name        string
+		query       string
+		accept      string
+		code        int
+		contentType string
+		contains    string
+	}{{
+		name:        "json",
+		query:       "?url=/hubs/42",
+		accept:      "",
+		code:        http.StatusOK,
+		contentType: "application/json",
+		contains:    `"full_pattern": "/hubs/{hubID}"`,
+	}, {
+		name:        "text",
+		query:       "?method=GET&url=%2Fhubs%2F42",
+		accept:      "text/plain",
+		code:        http.StatusOK,
+		contentType: "text/plain; charset=utf-8",
+		contains:    "pattern     /hubs/{hubID}\n",
+	}, {
+		name:        "no route",
+		query:       "?method=DELETE&url=/hubs/42",
+		accept:      "",
+		code:        http.StatusNotFound,
+		contentType: "text/plain; charset=utf-8",
+		contains:    "no route matches",
+	}, {
+		name:        "no url",
+		query:       "",
+		accept:      "",
+		code:        http.StatusBadRequest,
+		contentType: "text/plain; charset=utf-8",
+		contains:    "url query parameter is required",
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/"+docgen.ExplainPath+c.query, nil)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != c.code {
+				t.Errorf("status = %d, want %d", w.Code, c.code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != c.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, c.contentType)
+			}
+			if body := w.Body.String(); !strings.Contains(body, c.contains) {
+				t.Errorf("body should contain %q, got:\n%s", c.contains, body)
+			}
+		})
+	}
+}
+
+func TestExplain_inlineMount(t *testing.T) {
+	t.Parallel()
+
+	sub := chi.NewRouter()
+	sub.Get("/{hubID}", hubIndexHandler)
+
+	r := chi.NewRouter()
+	r.With(Auth).Route("/admin", func(r chi.Router) {
+		r.Use(RequestID)
+		r.Get("/{hubID}", hubIndexHandler)
+	})
+	r.With(Auth).Mount("/hubs", sub)
+
+	cases := []struct {
+		url  string
+		want []string
+	}{
+		{url: "/admin/42", want: []string{"Auth /admin/* true", "RequestID /admin false"}},
+		{url: "/hubs/42", want: []string{"Auth /hubs/* true"}},
+	}
+
+	for _, c := range cases {
+		e, err := docgen.Explain(r, "GET", c.url)
+		if err != nil {
+			t.Fatalf("Explain(%s) error = %v", c.url, err)
+		}
+
+		got := make([]string, len(e.Middlewares))
+		for i, mw := range e.Middlewares {
+			got[i] = fmt.Sprintf("%s %s %t", strings.TrimPrefix(mw.Func, testPkg+"."), mw.Pattern, mw.Inline)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Explain(%s) middlewares = %q, want %q", c.url, got, c.want)
+		}
+	}
+}
+
+func TestExplain_mountStub(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Route("/hubs", func(r chi.Router) {
+		r.Use(Auth)
+		r.Get("/", hubIndexHandler)
+	})
+
+	for _, url := range []string{"/hubs", "/hubs/"} {
+		e, err := docgen.Explain(r, "GET", url)
+		if err != nil {
+			t.Fatalf("Explain(%s) error = %v", url, err)
+		}
+
+		if e.FullPattern != "/hubs/" || !reflect.DeepEqual(e.RoutePatterns, []string{"/hubs/*", "/"}) {
+			t.Errorf("Explain(%s) pattern = %s %q, want /hubs/ [/hubs/* /]", url, e.FullPattern, e.RoutePatterns)
+		}
+		if len(e.Middlewares) != 1 || e.Middlewares[0].Pattern != "/hubs" {
+			t.Errorf("Explain(%s) middlewares = %+v, want Auth of /hubs", url, e.Middlewares)
+		}
+		if e.Handler.Func != testPkg+".hubIndexHandler" {
+			t.Errorf("Explain(%s) handler = %s, want hubIndexHandler", url, e.Handler.Func)
+		}
+	}
+}
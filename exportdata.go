@@ -0,0 +1,144 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// exportLoader imports the packages from the export data compiled by the go command,
+// as BuildDocFromSource and SchemaResolver do, so both see the same packages.
+type exportLoader struct {
+	fset     *token.FileSet
+	importer types.Importer
+	listed   map[string]*listedPackage // import path : package listed by the go command
+}
+
+func newExportLoader(fset *token.FileSet) *exportLoader {
+	el := &exportLoader{
+		fset:     fset,
+		importer: nil,
+		listed:   map[string]*listedPackage{},
+	}
+	el.importer = importer.ForCompiler(fset, "gc", el.lookup)
+
+	return el
+}
+
+// listedPackage is a package described by `go list -json`.
+type listedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+	Imports    []string          // resolved import paths
+	Export     string            // compiled export data, read by the importer
+	ImportMap  map[string]string // import path in the source : resolved import path, e.g. vendored
+	DepOnly    bool
+	Error      *struct{ Err string }
+}
+
+// list describes the package pkg and its dependencies with `go list -export -deps`
+// run in srcDir, so the build flags of GOFLAGS apply and the dependencies are
+// compiled once in the build cache. It returns the package pkg.
+func (el *exportLoader) list(pkg, srcDir string) (*listedPackage, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-e", "-export", "-deps", "-json", "--", pkg)
+	cmd.Dir = srcDir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("docgen: go list %s: %w\n%s", pkg, err, stderr.String())
+	}
+
+	var roots []*listedPackage
+
+	for dec := json.NewDecoder(&stdout); dec.More(); {
+		lp := &listedPackage{}
+		if err := dec.Decode(lp); err != nil {
+			return nil, fmt.Errorf("docgen: go list %s: %w", pkg, err)
+		}
+
+		if _, ok := el.listed[lp.ImportPath]; !ok {
+			el.listed[lp.ImportPath] = lp
+		}
+		if !lp.DepOnly {
+			roots = append(roots, lp)
+		}
+	}
+
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("docgen: %d packages match %s", len(roots), pkg)
+	}
+
+	return roots[0], nil
+}
+
+// lookup opens the export data of a listed package for the importer.
+func (el *exportLoader) lookup(importPath string) (io.ReadCloser, error) {
+	lp, ok := el.listed[importPath]
+	if !ok || lp.Export == "" {
+		return nil, fmt.Errorf("docgen: no export data for %s", importPath)
+	}
+
+	f, err := os.Open(lp.Export)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: %w", err)
+	}
+
+	return f, nil
+}
+
+// importPackage imports the package importPath, listed in srcDir with its dependencies when needed.
+func (el *exportLoader) importPackage(importPath, srcDir string) (*types.Package, error) {
+	if _, ok := el.listed[importPath]; !ok {
+		lp, err := el.list(importPath, srcDir)
+		if err != nil {
+			return nil, err
+		}
+		if lp.Error != nil {
+			return nil, fmt.Errorf("docgen: %s", lp.Error.Err)
+		}
+	}
+
+	return el.importer.Import(importPath) //nolint:wrapcheck // error of the importer
+}
+
+// importedPath returns the import path of the package imported by the listed package pkgPath
+// as qualifier, its package name or import path, "" if none.
+// The export data cannot tell, it only knows the imports used by the exported declarations.
+func (el *exportLoader) importedPath(pkgPath, qualifier string) string {
+	lp, ok := el.listed[pkgPath]
+	if !ok {
+		return ""
+	}
+
+	for _, imp := range lp.Imports {
+		if dep, ok := el.listed[imp]; imp == qualifier || ok && dep.Name == qualifier {
+			return imp
+		}
+	}
+
+	return ""
+}
+
+// importerMap resolves the import paths of a package, such as the vendored ones, before importing.
+type importerMap struct {
+	types.Importer
+	paths map[string]string
+}
+
+func (im importerMap) Import(importPath string) (*types.Package, error) {
+	if resolved, ok := im.paths[importPath]; ok {
+		importPath = resolved
+	}
+
+	return im.Importer.Import(importPath) //nolint:wrapcheck // error of the importer
+}
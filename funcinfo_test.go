@@ -46,7 +46,7 @@ func Test_getCallerFrame(t *testing.T) {
 	}
 }
 
-func Test_getPkgName(t *testing.T) {
+func Test_sourceFile_pkgName(t *testing.T) {
 	type args struct {
 		file string
 	}
@@ -59,14 +59,14 @@ func Test_getPkgName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getPkgName(tt.args.file); got != tt.want {
-				t.Errorf("getPkgName() = %v, want %v", got, tt.want)
+			if got := (&Resolver{}).source(tt.args.file).pkgName(); got != tt.want {
+				t.Errorf("pkgName() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_getFuncComment(t *testing.T) {
+func Test_sourceFile_funcComment(t *testing.T) {
 	type args struct {
 		file string
 		line int
@@ -80,8 +80,8 @@ func Test_getFuncComment(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, _ := getFuncComment(nil, tt.args.file, tt.args.line); got != tt.want {
-				t.Errorf("getFuncComment() = %v, want %v", got, tt.want)
+			if got := (&Resolver{}).source(tt.args.file).funcComment(nil, tt.args.line); got != tt.want {
+				t.Errorf("funcComment() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,123 @@
+package docgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Generator generates the documentation of a chi router in a given format.
+// Contrary to the *RoutesDoc helpers, the errors are returned to the caller
+// so a CI pipeline can fail instead of shipping a blank documentation.
+type Generator interface {
+	Generate(r chi.Routes) ([]byte, error)
+}
+
+// DocGenerator generates the documentation of a Doc built beforehand,
+// such as the Doc built by BuildDocFromSource without running the application.
+type DocGenerator interface {
+	GenerateDoc(doc Doc) ([]byte, error)
+}
+
+// JSONGenerator generates the indented JSON of the Doc.
+type JSONGenerator struct{}
+
+// MarkdownGenerator generates the Markdown documentation.
+type MarkdownGenerator struct {
+	Opts MarkdownOpts
+}
+
+// MarkupGenerator generates the HTML documentation.
+type MarkupGenerator struct {
+	Opts MarkupOpts
+}
+
+// Generate implements Generator.
+func (g JSONGenerator) Generate(r chi.Routes) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("docgen: router is nil")
+	}
+
+	doc, err := BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GenerateDoc(doc)
+}
+
+// GenerateDoc implements DocGenerator.
+func (JSONGenerator) GenerateDoc(doc Doc) ([]byte, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("docgen: json.MarshalIndent: %w", err)
+	}
+
+	return b, nil
+}
+
+// Generate implements Generator.
+func (g MarkdownGenerator) Generate(r chi.Routes) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("docgen: router is nil")
+	}
+
+	doc, err := BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GenerateDoc(doc)
+}
+
+// GenerateDoc implements DocGenerator.
+func (g MarkdownGenerator) GenerateDoc(doc Doc) ([]byte, error) {
+	md := &MarkdownDoc{
+		Opts:   g.Opts,
+		Router: nil,
+		Doc:    doc,
+		Routes: map[string]DocRouter{},
+		buf:    &bytes.Buffer{},
+	}
+
+	if err := md.GenerateDoc(doc); err != nil {
+		return nil, err
+	}
+
+	return md.buf.Bytes(), nil
+}
+
+// Generate implements Generator.
+func (g MarkupGenerator) Generate(r chi.Routes) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("docgen: router is nil")
+	}
+
+	doc, err := BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.GenerateDoc(doc)
+}
+
+// GenerateDoc implements DocGenerator.
+func (g MarkupGenerator) GenerateDoc(doc Doc) ([]byte, error) {
+	mu := &MarkupDoc{
+		Opts:          g.Opts,
+		Router:        nil,
+		Doc:           doc,
+		Routes:        map[string]DocRouter{},
+		FormattedHTML: "",
+		RouteHTML:     "",
+	}
+
+	if err := mu.generateDoc(doc); err != nil {
+		return nil, err
+	}
+
+	return []byte(mu.FormattedHTML), nil
+}
@@ -0,0 +1,93 @@
+package docgen_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		gen      docgen.Generator
+		routes   chi.Routes
+		contains string
+		wantErr  bool
+	}{{
+		name:     "json",
+		gen:      docgen.JSONGenerator{},
+		routes:   setupRouter(),
+		contains: `"/favicon.ico"`,
+		wantErr:  false,
+	}, {
+		name:     "json nil router",
+		gen:      docgen.JSONGenerator{},
+		routes:   nil,
+		contains: "",
+		wantErr:  true,
+	}, {
+		name:     "markdown",
+		gen:      docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{}},
+		routes:   setupRouter(),
+		contains: "<summary>`/favicon.ico`</summary>",
+		wantErr:  false,
+	}, {
+		name:     "markdown nil router",
+		gen:      docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{}},
+		routes:   nil,
+		contains: "",
+		wantErr:  true,
+	}, {
+		name:     "html",
+		gen:      docgen.MarkupGenerator{Opts: *buildOptions()},
+		routes:   setupRouter(),
+		contains: `<a href="#route-favicon-ico">/favicon.ico</a>`,
+		wantErr:  false,
+	}, {
+		name:     "html nil router",
+		gen:      docgen.MarkupGenerator{Opts: *buildOptions()},
+		routes:   nil,
+		contains: "",
+		wantErr:  true,
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := c.gen.Generate(c.routes)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if err != nil && got != nil {
+				t.Errorf("Generate() = %s with the error %v, want nil", got, err)
+			}
+
+			if !strings.Contains(string(got), c.contains) {
+				t.Errorf("Generate() = %s, want it to contain %s", got, c.contains)
+			}
+		})
+	}
+}
+
+func TestJSONGenerator_Generate(t *testing.T) {
+	t.Parallel()
+
+	b, err := docgen.JSONGenerator{}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Errorf("Generate() produced invalid JSON: %v", err)
+	}
+}
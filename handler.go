@@ -0,0 +1,322 @@
+package docgen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Files served by Handler whatever the HandlerOpts.
+const (
+	HTMLFile     = "index.html"
+	JSONFile     = "doc.json"
+	MarkdownFile = "doc.md"
+)
+
+// ExplainPath is the path of the Handler endpoint explaining a request, see Explain.
+const ExplainPath = "explain"
+
+// HandlerOpts configures the documentation served by Handler.
+type HandlerOpts struct {
+	Markdown MarkdownOpts
+	Markup   MarkupOpts
+
+	// Formats adds files to the HTML, JSON and Markdown documentation,
+	// by file name, such as "openapi.json" with an openapi.Generator.
+	Formats map[string]Format
+}
+
+// Format is a documentation file served by Handler.
+type Format struct {
+	ContentType string
+	Generator   DocGenerator
+}
+
+// Handler serves the documentation of the router r, e.g. r.Mount("/_docs", docgen.Handler(r, opts)):
+//
+//	/_docs/            the format negotiated from the Accept header, HTML by default
+//	/_docs/index.html  HTML
+//	/_docs/doc.json    JSON
+//	/_docs/doc.md      Markdown
+//	/_docs/<name>      the HandlerOpts.Formats
+//
+//	/_docs/explain?method=GET&url=/articles/42
+//
+// explains a request (see Explain) in JSON, or in text when the Accept header prefers text/plain.
+//
+// The Doc is built on the first request, once all the routes are registered,
+// and each file is generated once. The responses have an ETag
+// so the clients polling the documentation get a 304 Not Modified.
+func Handler(r chi.Routes, opts HandlerOpts) http.Handler {
+	dh := &docHandler{
+		routes: r,
+		formats: map[string]Format{
+			HTMLFile:     {ContentType: "text/html; charset=utf-8", Generator: MarkupGenerator{Opts: opts.Markup}},
+			JSONFile:     {ContentType: "application/json", Generator: JSONGenerator{}},
+			MarkdownFile: {ContentType: "text/markdown; charset=utf-8", Generator: MarkdownGenerator{Opts: opts.Markdown}},
+		},
+		names:   []string{HTMLFile, JSONFile, MarkdownFile},
+		once:    sync.Once{},
+		doc:     Doc{},
+		docErr:  nil,
+		mu:      sync.Mutex{},
+		files:   map[string]*docFile{},
+		started: time.Now(),
+	}
+
+	for _, name := range sortedNames(opts.Formats) {
+		if _, ok := dh.formats[name]; !ok {
+			dh.names = append(dh.names, name)
+		}
+		dh.formats[name] = opts.Formats[name]
+	}
+
+	// HEAD is served by the GET handlers, e.g. for the probes checking the ETag
+	mux := chi.NewRouter()
+	mux.Use(middleware.GetHead)
+	mux.Get("/", dh.negotiate)
+	mux.Get("/"+ExplainPath, dh.explain)
+	mux.Get("/{file}", dh.serveFile)
+
+	return mux
+}
+
+type docHandler struct {
+	routes  chi.Routes
+	formats map[string]Format
+	names   []string // order of preference of the formats
+
+	once   sync.Once
+	doc    Doc
+	docErr error
+
+	mu    sync.Mutex
+	files map[string]*docFile // cache of the generated files
+
+	started time.Time // Last-Modified of the files
+}
+
+// docFile is a generated file.
+type docFile struct {
+	content []byte
+	etag    string
+}
+
+// negotiate serves the format of the Accept header.
+func (dh *docHandler) negotiate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
+	name := dh.accepted(r.Header.Get("Accept"))
+	if name == "" {
+		http.Error(w, "docgen: no documentation format matches "+r.Header.Get("Accept"), http.StatusNotAcceptable)
+
+		return
+	}
+
+	dh.serve(w, r, name)
+}
+
+func (dh *docHandler) serveFile(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "file")
+	if _, ok := dh.formats[name]; !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	dh.serve(w, r, name)
+}
+
+func (dh *docHandler) serve(w http.ResponseWriter, r *http.Request, name string) {
+	f, err := dh.file(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", dh.formats[name].ContentType)
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("Cache-Control", "no-cache") // revalidate with If-None-Match
+
+	// ServeContent replies 304 when If-None-Match matches the ETag
+	http.ServeContent(w, r, name, dh.started, bytes.NewReader(f.content))
+}
+
+// explain serves the Explanation of the request of the url and method query parameters.
+func (dh *docHandler) explain(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
+	query := r.URL.Query()
+	if query.Get("url") == "" {
+		http.Error(w, "docgen: the url query parameter is required", http.StatusBadRequest)
+
+		return
+	}
+
+	method := query.Get("method")
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	e, err := Explain(dh.routes, method, query.Get("url"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	if prefersText(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write(e.Text())
+
+		return
+	}
+
+	b, err := e.JSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// prefersText reports whether the Accept header prefers text/plain to JSON.
+func prefersText(accept string) bool {
+	for _, mr := range parseAccept(accept) {
+		if matchMediaType(mr, "application/json") {
+			return false
+		}
+		if matchMediaType(mr, "text/plain") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// file returns the generated file, generating it on the first call.
+func (dh *docHandler) file(name string) (*docFile, error) {
+	dh.once.Do(func() {
+		dh.doc, dh.docErr = BuildDoc(dh.routes)
+	})
+	if dh.docErr != nil {
+		return nil, dh.docErr
+	}
+
+	dh.mu.Lock()
+	defer dh.mu.Unlock()
+
+	if f, ok := dh.files[name]; ok {
+		return f, nil
+	}
+
+	b, err := dh.formats[name].Generator.GenerateDoc(dh.doc)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(b)
+	f := &docFile{
+		content: b,
+		etag:    strconv.Quote(hex.EncodeToString(sum[:16])),
+	}
+	dh.files[name] = f
+
+	return f, nil
+}
+
+// accepted returns the name of the preferred format of the Accept header,
+// or an empty name when none is acceptable.
+func (dh *docHandler) accepted(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return HTMLFile
+	}
+
+	ranges := parseAccept(accept)
+	for _, mr := range ranges {
+		for _, name := range dh.names {
+			if matchMediaType(mr, dh.formats[name].ContentType) {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseAccept returns the media ranges of an Accept header
+// accepted by the client (q > 0), by decreasing quality.
+func parseAccept(accept string) []string {
+	type mediaRange struct {
+		typ string
+		q   float64
+	}
+
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		typ, params, _ := strings.Cut(part, ";")
+		mr := mediaRange{typ: strings.ToLower(strings.TrimSpace(typ)), q: 1}
+
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					mr.q = q
+				}
+			}
+		}
+
+		if mr.typ != "" && mr.q > 0 {
+			ranges = append(ranges, mr)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	types := make([]string, len(ranges))
+	for i, mr := range ranges {
+		types[i] = mr.typ
+	}
+
+	return types
+}
+
+// matchMediaType reports whether the content type matches
+// the media range such as "text/html", "text/*" or "*/*".
+func matchMediaType(mediaRange, contentType string) bool {
+	typ, _, _ := strings.Cut(contentType, ";")
+	typ = strings.ToLower(strings.TrimSpace(typ))
+
+	switch {
+	case mediaRange == "*/*":
+		return true
+	case strings.HasSuffix(mediaRange, "/*"):
+		return strings.HasPrefix(typ, strings.TrimSuffix(mediaRange, "*"))
+	default:
+		return mediaRange == typ
+	}
+}
+
+func sortedNames(formats map[string]Format) []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
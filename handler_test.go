@@ -0,0 +1,155 @@
+package docgen_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/openapi"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	r := setupRouter()
+	r.Mount("/_docs", docgen.Handler(r, docgen.HandlerOpts{
+		Formats: map[string]docgen.Format{
+			"openapi.yaml": {ContentType: "application/yaml", Generator: openapi.Generator{Opts: openapi.Options{}, YAML: true}},
+		},
+	}))
+
+	cases := []struct {
+		name        string
+		path        string
+		accept      string
+		code        int
+		contentType string
+		contains    string
+	}{{
+		name:        "default",
+		path:        "/_docs",
+		accept:      "",
+		code:        http.StatusOK,
+		contentType: "text/html; charset=utf-8",
+		contains:    "<html",
+	}, {
+		name:        "negotiated json",
+		path:        "/_docs/",
+		accept:      "text/html;q=0.5, application/json",
+		code:        http.StatusOK,
+		contentType: "application/json",
+		contains:    `"/hubs/{hubID}/view"`,
+	}, {
+		name:        "negotiated format",
+		path:        "/_docs/",
+		accept:      "application/*;q=0.9, application/yaml",
+		code:        http.StatusOK,
+		contentType: "application/yaml",
+		contains:    "openapi: 3.1.0",
+	}, {
+		name:        "markdown",
+		path:        "/_docs/doc.md",
+		accept:      "",
+		code:        http.StatusOK,
+		contentType: "text/markdown; charset=utf-8",
+		contains:    "`/hubs/{hubID}/view`",
+	}, {
+		name:        "not acceptable",
+		path:        "/_docs/",
+		accept:      "image/png",
+		code:        http.StatusNotAcceptable,
+		contentType: "text/plain; charset=utf-8",
+		contains:    "image/png",
+	}, {
+		name:        "unknown file",
+		path:        "/_docs/doc.pdf",
+		accept:      "",
+		code:        http.StatusNotFound,
+		contentType: "text/plain; charset=utf-8",
+		contains:    "not found",
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			if c.accept != "" {
+				req.Header.Set("Accept", c.accept)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != c.code {
+				t.Errorf("status = %d, want %d", w.Code, c.code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != c.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, c.contentType)
+			}
+			if body := w.Body.String(); !strings.Contains(body, c.contains) {
+				t.Errorf("body should contain %q, got:\n%s", c.contains, body)
+			}
+		})
+	}
+}
+
+func TestHandler_head(t *testing.T) {
+	t.Parallel()
+
+	r := setupRouter()
+	r.Mount("/_docs", docgen.Handler(r, docgen.HandlerOpts{}))
+
+	cases := []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/_docs/", contentType: "text/html; charset=utf-8"},
+		{path: "/_docs/doc.json", contentType: "application/json"},
+		{path: "/_docs/explain?url=/favicon.ico", contentType: "application/json"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodHead, c.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("HEAD %s: status = %d, want %d", c.path, w.Code, http.StatusOK)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != c.contentType {
+			t.Errorf("HEAD %s: Content-Type = %q, want %q", c.path, ct, c.contentType)
+		}
+	}
+}
+
+func TestHandler_etag(t *testing.T) {
+	t.Parallel()
+
+	r := setupRouter()
+	h := docgen.Handler(r, docgen.HandlerOpts{})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/doc.json", nil))
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q", w.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want 304 Not Modified", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %s, want empty", w.Body.String())
+	}
+}
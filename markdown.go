@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -34,25 +36,30 @@ type MarkdownOpts struct {
 	// For example:
 	// map[string]string{"github.com/my/package/vendor/go-chi/chi/": "https://github.com/go-chi/chi/blob/master/"}
 	URLMap map[string]string
+
+	// SchemaDir is a directory of the Go module declaring the types of the
+	// @body and @response annotations, their JSON Schema are written when not empty.
+	SchemaDir string
+
+	// MethodOrder sorts the methods of a route, DefaultMethodOrder when nil.
+	MethodOrder []string
+
+	// Diagram adds a Mermaid flowchart of the routers and middlewares,
+	// see DiagramGenerator.
+	Diagram bool
+
+	// Template renders the Markdown, MarkdownTemplate when nil.
+	// Its "markdown" template is executed with a MarkdownData.
+	Template *template.Template
 }
 
 func MarkdownRoutesDoc(r chi.Router, opts MarkdownOpts) string {
-	md := &MarkdownDoc{
-		Opts:   opts,
-		Router: r,
-		Doc: Doc{Router: DocRouter{
-			Middlewares: []DocMiddleware{},
-			Routes:      map[string]DocRoute{},
-		}},
-		Routes: map[string]DocRouter{},
-		buf:    &bytes.Buffer{},
-	}
-
-	if err := md.Generate(); err != nil {
+	b, err := MarkdownGenerator{Opts: opts}.Generate(r)
+	if err != nil {
 		return fmt.Sprintf("ERROR: %s\n", err.Error())
 	}
 
-	return md.String()
+	return string(b)
 }
 
 func (md *MarkdownDoc) String() string {
@@ -69,132 +76,237 @@ func (md *MarkdownDoc) Generate() error {
 		return err
 	}
 
+	return md.GenerateDoc(doc)
+}
+
+// GenerateDoc writes the markdown of a Doc built beforehand, e.g. by BuildDocFromSource.
+func (md *MarkdownDoc) GenerateDoc(doc Doc) error {
+	if md.Opts.SchemaDir != "" {
+		if err := ResolveSchemas(&doc, md.Opts.SchemaDir); err != nil {
+			return err
+		}
+	}
+
 	md.Doc = doc
 	md.buf = &bytes.Buffer{}
-	md.Routes = make(map[string]DocRouter)
-
-	md.WriteIntro()
-	md.WriteRoutes()
 
-	return nil
+	return md.execute("markdown", md.data())
 }
 
+// WriteIntro writes the "intro" template.
 func (md *MarkdownDoc) WriteIntro() {
-	pkgName := md.Opts.ProjectPath
-	md.buf.WriteString(fmt.Sprintf("# %s\n\n", pkgName))
-
-	intro := md.Opts.Intro
-	md.buf.WriteString(fmt.Sprintf("%s\n\n", intro))
+	md.write("intro", md.data())
 }
 
+// WriteRoutes writes the "routes" template.
 func (md *MarkdownDoc) WriteRoutes() {
-	md.buf.WriteString("## Routes\n\n")
-
-	var buildRoutesMap func(parentPattern string, ar, nr, dr *DocRouter)
-	buildRoutesMap = func(parentPattern string, ar, nr, dr *DocRouter) {
-		nr.Middlewares = append(nr.Middlewares, dr.Middlewares...)
-
-		for pat, rt := range dr.Routes {
-			pattern := parentPattern + pat
-
-			nr.Routes = DocRoutes{}
-
-			if rt.Router != nil {
-				nnr := &DocRouter{
-					Middlewares: []DocMiddleware{},
-					Routes:      map[string]DocRoute{},
-				}
-				nr.Routes[pat] = DocRoute{
-					Pattern:  pat,
-					Handlers: rt.Handlers,
-					Router:   nnr,
-				}
-				buildRoutesMap(pattern, ar, nnr, rt.Router)
-			} else if len(rt.Handlers) > 0 {
-				nr.Routes[pat] = DocRoute{
-					Pattern:  pat,
-					Handlers: rt.Handlers,
-					Router:   nil,
-				}
-
-				// Remove the trailing slash if the handler is a subroute for "/"
-				routeKey := pattern
-				if pat == "/" && len(routeKey) > 1 {
-					routeKey = routeKey[:len(routeKey)-1]
-				}
-				md.Routes[routeKey] = copyDocRouter(*ar)
-			} else {
-				panic("not possible")
-			}
-		}
+	md.write("routes", md.data())
+}
+
+// writeParams writes the "params" template,
+// the table of the URL parameters of a route, nothing when it has no parameter.
+func (md *MarkdownDoc) writeParams(mr MarkdownRoute) {
+	md.write("params", mr.Params)
+}
+
+// writeAnnotations writes the "annotations" template, the annotations of the handlers of a route.
+func (md *MarkdownDoc) writeAnnotations(mr MarkdownRoute) {
+	md.write("annotations", mr.Handlers)
+}
+
+// writeSchemas writes the "schemas" template, the JSON Schema of the types set by ResolveSchemas.
+func (md *MarkdownDoc) writeSchemas() {
+	md.write("schemas", md.schemas())
+}
+
+// write executes a template, logging the error as the other Write methods cannot return it.
+func (md *MarkdownDoc) write(name string, data any) {
+	if err := md.execute(name, data); err != nil {
+		log.Print(err)
 	}
+}
 
-	// Build a route tree that consists of the full route pattern
-	// and the part of the tree for just that specific route, stored
-	// in routes map on the markdown struct. This is the structure we
-	// are going to render to markdown.
-	dr := md.Doc.Router
-	ar := DocRouter{
-		Middlewares: []DocMiddleware{},
-		Routes:      map[string]DocRoute{},
+// execute executes a template of MarkdownOpts.Template, or of the default MarkdownTemplate,
+// with the template funcs bound to the MarkdownOpts.
+func (md *MarkdownDoc) execute(name string, data any) error {
+	t := md.Opts.Template
+	if t == nil {
+		t = defaultMarkdownTemplate
 	}
-	buildRoutesMap("", &ar, &ar, &dr)
 
-	// Generate the markdown to render the above structure
-	var printRouter func(depth int, dr DocRouter)
-	printRouter = func(depth int, dr DocRouter) {
-		tabs := ""
-		for i := 0; i < depth; i++ {
-			tabs += "\t"
-		}
+	t, err := t.Clone()
+	if err != nil {
+		return fmt.Errorf("docgen: %w", err)
+	}
 
-		// Middlewares
-		for _, mw := range dr.Middlewares {
-			md.buf.WriteString(fmt.Sprintf("%s- [%s](%s)\n", tabs, mw.Func, md.githubSourceURL(mw.File, mw.Line)))
-		}
+	if err := t.Funcs(md.funcs()).ExecuteTemplate(md.buf, name, data); err != nil {
+		return fmt.Errorf("docgen: %w", err)
+	}
 
-		// Routes
-		for _, rt := range dr.Routes {
-			md.buf.WriteString(fmt.Sprintf("%s- **%s**\n", tabs, normalizer(rt.Pattern)))
+	return nil
+}
 
-			if rt.Router != nil {
-				printRouter(depth+1, *rt.Router)
+// data returns the data of the "markdown" template.
+func (md *MarkdownDoc) data() MarkdownData {
+	diagram := ""
+	if md.Opts.Diagram {
+		diagram = string(newDiagram(md.Doc, md.Opts.MethodOrder).mermaid())
+	}
+
+	return MarkdownData{
+		Title:   md.Opts.ProjectPath,
+		Intro:   md.Opts.Intro,
+		Diagram: diagram,
+		Routes:  md.routes(),
+		Schemas: md.schemas(),
+		Doc:     md.Doc,
+	}
+}
+
+// routes walks the Doc to list the routes with handlers, sorted by full pattern,
+// each with the tree of the routers leading to its handlers, also stored in md.Routes.
+// The full pattern concatenates the patterns of the routes, e.g. "/articles/*/{articleID}".
+func (md *MarkdownDoc) routes() []MarkdownRoute {
+	md.Routes = map[string]DocRouter{}
+
+	var (
+		routes   []MarkdownRoute
+		indexes  = map[string]int{} // full pattern : index in routes
+		routers  []DocRouter        // from the root to the visited router
+		patterns []string           // of the routes from the root to the visited route
+		current  int                // index of the visited route in routes
+		leaf     *MarkdownTreeRoute // visited route in its tree, nil for a sub-router
+	)
+
+	_ = Walker{MethodOrder: md.Opts.MethodOrder}.Walk(md.Doc, VisitorFuncs{ // the funcs return no error
+		EnterRouterFunc: func(_ WalkState, dr DocRouter) error {
+			routers = append(routers, dr)
+
+			return nil
+		},
+		LeaveRouterFunc: func(WalkState, DocRouter) error {
+			routers = routers[:len(routers)-1]
+
+			return nil
+		},
+		VisitRouteFunc: func(s WalkState, rt DocRoute) error {
+			patterns = append(patterns[:s.Depth], rt.Pattern)
+			leaf = nil
+
+			if len(rt.Handlers) == 0 {
+				return nil
+			}
+
+			pattern := routeKey(strings.Join(patterns, ""), rt.Pattern)
+			md.Routes[pattern] = routeTree(routers, patterns, rt.Handlers)
+
+			mr := MarkdownRoute{
+				Pattern:  pattern,
+				Tree:     tree(routers, patterns),
+				Params:   nil,
+				Handlers: make([]DocHandler, 0, len(rt.Handlers)),
+			}
+
+			var ok bool
+			if current, ok = indexes[pattern]; ok {
+				routes[current] = mr // replaces the route of the same full pattern
 			} else {
-				for meth, dh := range rt.Handlers {
-					md.buf.WriteString(fmt.Sprintf("%s\t- _%s_\n", tabs, meth))
+				current = len(routes)
+				indexes[pattern] = current
+				routes = append(routes, mr)
+			}
 
-					// Handler middlewares
-					for _, mw := range dh.Middlewares {
-						md.buf.WriteString(fmt.Sprintf("%s\t\t- [%s](%s)\n", tabs, mw.Func, md.githubSourceURL(mw.File, mw.Line)))
-					}
+			leaf = &routes[current].Tree.Routes[0]
+			for leaf.Router != nil {
+				leaf = &leaf.Router.Routes[0]
+			}
 
-					// Handler endpoint
-					md.buf.WriteString(fmt.Sprintf("%s\t\t- [%s](%s)\n", tabs, dh.Func, md.githubSourceURL(dh.File, dh.Line)))
-				}
+			return nil
+		},
+		VisitHandlerFunc: func(s WalkState, dh DocHandler) error {
+			if leaf == nil {
+				return nil // the handlers of the sub-router are listed
 			}
+
+			leaf.Handlers = append(leaf.Handlers, MarkdownHandler{Depth: s.Depth, DocHandler: dh})
+			routes[current].Handlers = append(routes[current].Handlers, dh)
+
+			return nil
+		},
+		VisitMiddlewareFunc: nil,
+	})
+
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Pattern < routes[j].Pattern })
+
+	for i := range routes {
+		if len(routes[i].Handlers) > 0 {
+			routes[i].Params = routes[i].Handlers[0].Params // same full pattern for all the methods
+		}
+	}
+
+	return routes
+}
+
+// tree returns the routers from the root to the visited route, with their middlewares
+// and a single route each, routes appends the handlers to the last route.
+func tree(routers []DocRouter, patterns []string) MarkdownTree {
+	var sub *MarkdownTree
+
+	for depth := len(routers) - 1; depth >= 0; depth-- {
+		sub = &MarkdownTree{
+			Depth:       depth,
+			Middlewares: routers[depth].middlewares(),
+			Routes: []MarkdownTreeRoute{{
+				Depth:    depth,
+				Pattern:  patterns[depth],
+				Router:   sub,
+				Handlers: nil,
+			}},
 		}
 	}
 
-	routePaths := []string{}
-	for pat := range md.Routes {
-		routePaths = append(routePaths, pat)
+	return *sub
+}
+
+// schemas returns the schemas set by ResolveSchemas, sorted by name.
+func (md *MarkdownDoc) schemas() []MarkdownSchema {
+	names := make([]string, 0, len(md.Doc.Schemas))
+	for name := range md.Doc.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	schemas := make([]MarkdownSchema, 0, len(names))
+	for _, name := range names {
+		schemas = append(schemas, MarkdownSchema{Name: name, Schema: md.Doc.Schemas[name]})
 	}
-	sort.Strings(routePaths)
 
-	for _, pat := range routePaths {
-		dr := md.Routes[pat]
-		md.buf.WriteString("<details>\n")
-		md.buf.WriteString(fmt.Sprintf("<summary>`%s`</summary>\n", pat))
-		md.buf.WriteString("\n")
-		printRouter(0, dr)
-		md.buf.WriteString("\n")
-		md.buf.WriteString("</details>\n")
+	return schemas
+}
+
+// schemaLink writes the type of a body in code, linked to
+// the section of its schema written by the "schemas" template if any.
+func schemaLink(typ string, s *Schema) string {
+	for s != nil && s.Items != nil {
+		s = s.Items
+	}
+
+	if s == nil || s.Ref == "" {
+		return "`" + typ + "`"
 	}
 
-	md.buf.WriteString("\n")
-	md.buf.WriteString(fmt.Sprintf("Total # of routes: %d\n", len(md.Routes)))
+	name := strings.TrimPrefix(s.Ref, SchemaRefPrefix)
+	anchor := strings.ToLower(strings.ReplaceAll(name, ".", ""))
+
+	return fmt.Sprintf("[`%s`](#%s)", typ, anchor)
+}
+
+func required(r bool) string {
+	if r {
+		return " (required)"
+	}
 
-	// TODO: total number of handlers..
+	return ""
 }
 
 func (md *MarkdownDoc) githubSourceURL(file string, line int) string {
@@ -205,7 +317,8 @@ func (md *MarkdownDoc) githubSourceURL(file string, line int) string {
 	if md.Opts.ProjectPath == "" {
 		return ""
 	}
-	for pkg, url := range md.Opts.URLMap {
+	for _, pkg := range urlMapKeys(md.Opts.URLMap) {
+		url := md.Opts.URLMap[pkg]
 		if idx := strings.Index(file, pkg); idx >= 0 {
 			pos := idx + len(pkg)
 			url = strings.TrimRight(url, "/")
@@ -0,0 +1,114 @@
+package docgen
+
+import (
+	_ "embed" // markdown.tmpl
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// MarkdownData is the data of the "markdown" template, see MarkdownTemplate.
+type MarkdownData struct {
+	Title   string           // MarkdownOpts.ProjectPath
+	Intro   string           // MarkdownOpts.Intro
+	Diagram string           // Mermaid flowchart when MarkdownOpts.Diagram is set
+	Routes  []MarkdownRoute  // sorted by pattern
+	Schemas []MarkdownSchema // set by ResolveSchemas, sorted by name
+	Doc     Doc
+}
+
+// MarkdownRoute is a route with handlers and the routers leading to them.
+type MarkdownRoute struct {
+	Pattern  string       // full pattern, e.g. "/articles/*/{articleID}/*"
+	Tree     MarkdownTree // routers from the root to the handlers
+	Params   []DocParam   // parameters of the full pattern
+	Handlers []DocHandler // sorted by MarkdownOpts.MethodOrder
+}
+
+// MarkdownTree is a router of a MarkdownRoute.Tree, Depth is its indentation level.
+type MarkdownTree struct {
+	Depth       int
+	Middlewares []DocMiddleware
+	Routes      []MarkdownTreeRoute
+}
+
+// MarkdownTreeRoute is a route of a MarkdownTree, with either a sub-Router or Handlers.
+type MarkdownTreeRoute struct {
+	Depth    int
+	Pattern  string // pattern relative to the parent router
+	Router   *MarkdownTree
+	Handlers []MarkdownHandler
+}
+
+// MarkdownHandler is a handler of a MarkdownTreeRoute.
+type MarkdownHandler struct {
+	Depth int
+	DocHandler
+}
+
+// MarkdownSchema is a JSON Schema set by ResolveSchemas.
+type MarkdownSchema struct {
+	Name   string
+	Schema *Schema
+}
+
+//go:embed markdown.tmpl
+var markdownTemplate string
+
+var defaultMarkdownTemplate = template.Must(
+	template.New("markdown.tmpl").Funcs((&MarkdownDoc{}).funcs()).Parse(markdownTemplate))
+
+// MarkdownTemplate returns a copy of the default templates of the Markdown documentation:
+//
+//	markdown     the whole document, executed with a MarkdownData
+//	intro        the title and the introduction (MarkdownData)
+//	diagram      the Mermaid flowchart, when MarkdownOpts.Diagram is set (string)
+//	routes       the list of the routes (MarkdownData)
+//	route        a route in a <details> element (MarkdownRoute)
+//	tree         the routers and middlewares leading to the handlers (MarkdownTree)
+//	handler      a method with its middlewares and handler function (MarkdownHandler)
+//	middleware   a middleware function (DocMiddleware)
+//	params       the table of the URL parameters ([]DocParam)
+//	annotations  the annotations of the handlers ([]DocHandler)
+//	schemas      the JSON Schemas ([]MarkdownSchema)
+//
+// Redefine some of them to adapt the layout, e.g.
+//
+//	t := template.Must(docgen.MarkdownTemplate().Parse(`{{define "intro"}}# API of {{.Title}}{{"\n\n"}}{{end}}`))
+//	md, err := docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{Template: t}}.Generate(r)
+//
+// The templates may call these funcs:
+//
+//	sourceURL file line       link to the source code, according to the MarkdownOpts
+//	normalizePattern pattern  pattern without the "/*" of the mount points
+//	indent depth              depth tabs
+//	schemaLink type schema    type in code, linked to its schema section
+//	required bool             " (required)" when true
+//	escapePipes text          text with the "|" escaped for a table cell
+//	join list separator       strings.Join
+//	json value                indented JSON
+func MarkdownTemplate() *template.Template {
+	return template.Must(defaultMarkdownTemplate.Clone())
+}
+
+// funcs returns the template funcs bound to the options of md.
+func (md *MarkdownDoc) funcs() template.FuncMap {
+	return template.FuncMap{
+		"sourceURL":        md.githubSourceURL,
+		"normalizePattern": normalizer,
+		"indent":           func(depth int) string { return strings.Repeat("\t", depth) },
+		"schemaLink":       schemaLink,
+		"required":         required,
+		"escapePipes":      func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+		"join":             strings.Join,
+		"json": func(v any) (string, error) {
+			b, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return "", fmt.Errorf("json.MarshalIndent: %w", err)
+			}
+
+			return string(b), nil
+		},
+	}
+}
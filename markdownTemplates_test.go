@@ -0,0 +1,67 @@
+package docgen_test
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestMarkdownTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(docgen.MarkdownTemplate().Parse(`
+{{- define "intro"}}= {{.Title}} ={{"\n\n"}}{{end}}
+{{- define "route"}}* {{normalizePattern .Pattern}}{{range .Handlers}} {{.Method}}{{end}}{{"\n"}}{{end}}`))
+
+	opts := docgen.MarkdownOpts{
+		ProjectPath:        "Hubs",
+		Intro:              "",
+		ForceRelativeLinks: false,
+		URLMap:             nil,
+		SchemaDir:          "",
+		MethodOrder:        nil,
+		Template:           tmpl,
+	}
+
+	b, err := docgen.MarkdownGenerator{Opts: opts}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := string(b)
+	for _, want := range []string{
+		"= Hubs =\n\n## Routes\n\n",
+		"* /hubs/{hubID}/view GET\n",
+		"\nTotal # of routes: ",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown should contain %q, got:\n%s", want, md)
+		}
+	}
+
+	if strings.Contains(md, "<details>") {
+		t.Errorf("the route template should be redefined, got:\n%s", md)
+	}
+
+	// the default template is not altered
+	b, err = docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{ProjectPath: "Hubs"}}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "# Hubs\n\n") || !strings.Contains(string(b), "<details>") {
+		t.Errorf("default Markdown:\n%s", b)
+	}
+}
+
+func TestMarkdownTemplate_error(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(docgen.MarkdownTemplate().Parse(`{{define "intro"}}{{.Missing}}{{end}}`))
+
+	_, err := docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{Template: tmpl}}.Generate(setupRouter())
+	if err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Generate() error = %v, want the template error", err)
+	}
+}
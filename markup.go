@@ -1,8 +1,9 @@
 package docgen
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
+	"html/template"
 	"sort"
 	"strings"
 
@@ -24,7 +25,7 @@ type MarkupOpts struct {
 	// ProjectPath is the base Go import path of the project
 	ProjectPath string
 
-	// Intro text included at the top of the generated markdown file.
+	// Intro HTML included at the top of the generated page, not escaped.
 	Intro string
 
 	// RouteText contains HTML generated from Route metadata
@@ -38,31 +39,45 @@ type MarkupOpts struct {
 	// For example:
 	// map[string]string{"github.com/my/package/vendor/go-chi/chi/": "https://github.com/go-chi/chi/blob/master/"}
 	URLMap map[string]string
+
+	// MethodOrder sorts the methods of a route, DefaultMethodOrder when nil.
+	MethodOrder []string
+
+	// Theme selects the CSS kit: ThemeBass (default) or ThemeMilligram.
+	Theme string
+
+	// CSS is appended to the CSS of the Theme, e.g. to change the colors.
+	CSS string
+
+	// Explorer embeds the Doc as JSON in the page with a small script
+	// to search the routes, filter them by method or middleware,
+	// and expand the route of the URL fragment. No CDN: it works offline.
+	Explorer bool
+
+	// TryItOut adds a console to each handler to send a request to the same origin
+	// and show the response: path parameters, query, headers and body.
+	// Do not enable it on a production service serving the page with Handler.
+	TryItOut bool
+
+	// Diagram adds a Mermaid flowchart of the routers and middlewares,
+	// see DiagramGenerator. It is drawn by the mermaid.js of MermaidURL,
+	// its source is displayed when MermaidURL is empty (no CDN by default).
+	Diagram    bool
+	MermaidURL string
+
+	// Template renders the HTML page, MarkupTemplate when nil.
+	// Its "page" template is executed with a MarkupData.
+	Template *template.Template
 }
 
 // MarkupRoutesDoc builds a document based on routes in a given router with given option set.
 func MarkupRoutesDoc(r chi.Router, opts MarkupOpts) string {
-	// TODO: rewrite this class to have a single exported function(?)
-	//    that returns a formatted HTML document based on a MarkupOpts
-	//     1) flatten router, build docs w/o recursion
-	//     2) Alternatively, build the JSON or Markdown doc and convert to HTML
-
-	mu := &MarkupDoc{
-		Opts:   opts,
-		Router: r,
-		Doc: Doc{Router: DocRouter{
-			Middlewares: []DocMiddleware{},
-			Routes:      map[string]DocRoute{},
-		}},
-		Routes:        map[string]DocRouter{},
-		FormattedHTML: "",
-		RouteHTML:     "",
-	}
-	if err := mu.generate(); err != nil {
+	b, err := MarkupGenerator{Opts: opts}.Generate(r)
+	if err != nil {
 		return fmt.Sprintf("ERROR: %s\n", err.Error())
 	}
 
-	return mu.String()
+	return string(b)
 }
 
 // String pretty prints the document.
@@ -70,143 +85,181 @@ func (mu *MarkupDoc) String() string {
 	return mu.FormattedHTML
 }
 
-// Generate builds the document.
-func (mu *MarkupDoc) generate() error {
-	if mu.Router == nil {
-		return errors.New("docgen: router is nil")
-	}
+// generateDoc builds the document of a Doc built beforehand.
+func (mu *MarkupDoc) generateDoc(doc Doc) error {
+	mu.Doc = doc
+	mu.Routes = make(map[string]DocRouter)
+	mu.RouteHTML = ""
 
-	var err error
-	mu.Doc, err = BuildDoc(mu.Router)
+	data, err := mu.data()
 	if err != nil {
 		return err
 	}
 
-	mu.Routes = make(map[string]DocRouter)
-	mu.writeRoutes()
-
-	r := strings.NewReplacer(
-		"{title}", "go-chi Docgen",
-		"{css}", BassCSS(),
-		"{intro}", "Ding! Routes are Done!",
-		"{routes}", mu.RouteHTML,
-		"{favicon.ico}", FaviconIcoData(),
-	)
+	t := mu.Opts.Template
+	if t == nil {
+		t = defaultMarkupTemplate
+	}
 
-	htmlString := r.Replace(BaseTemplate)
-	mu.FormattedHTML = htmlString
+	t, err = t.Clone()
+	if err != nil {
+		return fmt.Errorf("docgen: %w", err)
+	}
+	t.Funcs(mu.funcs())
+
+	var buf bytes.Buffer
+
+	if t.Lookup("routes") != nil {
+		if err := t.ExecuteTemplate(&buf, "routes", data); err != nil {
+			return fmt.Errorf("docgen: %w", err)
+		}
+		mu.RouteHTML = buf.String()
+		buf.Reset()
+	}
+
+	if err := t.ExecuteTemplate(&buf, "page", data); err != nil {
+		return fmt.Errorf("docgen: %w", err)
+	}
+	mu.FormattedHTML = buf.String()
 
 	return nil
 }
 
-// writeRoutes generates the string for the Routes.
-func (mu *MarkupDoc) writeRoutes() {
-	routesHeader := Head(2, "Routes")
-	mu.RouteHTML += routesHeader
-	// mu.Buf.WriteString(routesHeader)
-
-	// Build a route tree that consists of the full route pattern
-	// and the part of the tree for just that specific route, stored
-	// in routes map on the markdown struct. This is the structure we
-	// are going to render to markdown.
-	dr := mu.Doc.Router
-	ar := DocRouter{
-		Middlewares: []DocMiddleware{},
-		Routes:      map[string]DocRoute{},
+// data returns the data of the "page" template.
+func (mu *MarkupDoc) data() (MarkupData, error) {
+	css, err := themeCSS(mu.Opts.Theme)
+	if err != nil {
+		return MarkupData{}, err
 	}
-	buildRoutesMap(mu, "", &ar, &ar, &dr)
 
-	routePaths := []string{}
-	for pat := range mu.Routes {
-		routePaths = append(routePaths, pat)
+	title := mu.Opts.ProjectPath
+	if title == "" {
+		title = "go-chi Docgen"
 	}
-	sort.Strings(routePaths)
 
-	for _, pat := range routePaths {
-		dr := mu.Routes[pat]
-		mu.RouteHTML += "<div>" + P(pat)
-		printRouter(mu, 0, dr)
-		mu.RouteHTML += "</div>"
+	routes, err := mu.routes()
+	if err != nil {
+		return MarkupData{}, err
 	}
-}
 
-// Generate the markup to render the above structure.
-func printRouter(mu *MarkupDoc, depth int, dr DocRouter) {
-	// Middlewares
-	middleWares := make([]string, len(dr.Middlewares))
-	for j, mw := range dr.Middlewares {
-		middleWares[j] = ListItem(fmt.Sprintf("[%s](%s)", mw.Func, mu.githubSourceURL(mw.File, mw.Line)))
-	}
-	middleWaresList := UnorderedList(strings.Join(middleWares, ""))
-	mu.RouteHTML += Div(Head(3, "Middlewares") + middleWaresList)
-	// Routes
-	routeListItems := make([]string, len(dr.Routes))
-	ri := -1
-	for _, rt := range dr.Routes {
-		ri++
-
-		// RECURSION AAAAHHHHH NOOOOO
-		if rt.Router != nil {
-			printRouter(mu, depth+1, *rt.Router)
-		} else {
-			// Route Handler Methods
-			methods := make([]string, len(rt.Handlers))
-			mi := -1
-			for meth, dh := range rt.Handlers {
-				mi++
-
-				innerMiddles := make([]string, len(dh.Middlewares))
-				imi := -1
-				// Handler middlewares
-				for _, mw := range dh.Middlewares {
-					imi++
-					innerMiddles[imi] = ListItem(fmt.Sprintf("[%s](%s)", mw.Func, mu.githubSourceURL(mw.File, mw.Line)))
-				}
-				innerMiddlesList := UnorderedList(strings.Join(innerMiddles, ""))
-				handlerEndpoint := fmt.Sprintf("[%s](%s)", dh.Func, mu.githubSourceURL(dh.File, dh.Line))
-				methods[mi] = ListItem(meth + " " + handlerEndpoint + "<br />" + Div(innerMiddlesList))
-			}
-			methodList := UnorderedList(strings.Join(methods, ""))
-			routeListItems[ri] = ListItem(rt.Pattern + "<br />" + methodList)
-		}
+	diagram := ""
+	if mu.Opts.Diagram {
+		diagram = string(newDiagram(mu.Doc, mu.Opts.MethodOrder).mermaid())
 	}
-	routeList := UnorderedList(strings.Join(routeListItems, ""))
-	mu.RouteHTML += Head(3, "Middlewares") + Div(middleWaresList) + Head(3, "Routes") + Div(routeList)
+
+	return MarkupData{
+		Title: title,
+		//nolint:gosec // the Intro is written by the developer, not extracted from the source code
+		Intro:   template.HTML(mu.Opts.Intro),
+		CSS:     template.CSS(css + LayoutCSS() + mu.Opts.CSS), //nolint:gosec // same
+		Favicon: template.URL(FaviconIcoData()),                //nolint:gosec // embedded image
+		Routes:  routes,
+
+		Diagram:    diagram,
+		MermaidURL: mu.Opts.MermaidURL,
+
+		Explorer: mu.Opts.Explorer,
+		Script:   template.JS(markupScript), //nolint:gosec // embedded script
+		Doc:      mu.Doc,
+
+		TryItOut:      mu.Opts.TryItOut,
+		ConsoleScript: template.JS(consoleScript), //nolint:gosec // embedded script
+	}, nil
 }
 
-func buildRoutesMap(mu *MarkupDoc, parentPattern string, ar, nr, dr *DocRouter) {
-	nr.Middlewares = append(nr.Middlewares, dr.Middlewares...)
+// routes walks the Doc to list the routes with handlers, sorted by pattern,
+// and stores the tree of each route in mu.Routes.
+func (mu *MarkupDoc) routes() ([]MarkupRoute, error) {
+	var (
+		routes   []MarkupRoute
+		indexes  = map[string]int{} // pattern : index in routes
+		routers  []DocRouter        // from the root to the visited router
+		patterns []string           // of the routes from the root to the visited route
+		route    DocRoute
+	)
+
+	err := Walker{MethodOrder: mu.Opts.MethodOrder}.Walk(mu.Doc, VisitorFuncs{
+		EnterRouterFunc: func(_ WalkState, dr DocRouter) error {
+			routers = append(routers, dr)
+
+			return nil
+		},
+		LeaveRouterFunc: func(WalkState, DocRouter) error {
+			routers = routers[:len(routers)-1]
 
-	for pat, rt := range dr.Routes {
-		pattern := parentPattern + pat
+			return nil
+		},
+		VisitRouteFunc: func(s WalkState, rt DocRoute) error {
+			patterns = append(patterns[:s.Depth], rt.Pattern)
+			route = rt
 
-		nr.Routes = DocRoutes{}
+			if len(rt.Handlers) == 0 {
+				return nil
+			}
+
+			pattern := routeKey(s.Pattern, rt.Pattern)
+			mu.Routes[pattern] = routeTree(routers, patterns, rt.Handlers)
 
-		if rt.Router != nil {
-			nnr := &DocRouter{}
-			nr.Routes[pat] = DocRoute{
-				Pattern:  pat,
-				Handlers: rt.Handlers,
-				Router:   nnr,
+			if _, ok := indexes[pattern]; !ok {
+				indexes[pattern] = len(routes)
+				routes = append(routes, MarkupRoute{ID: "", Pattern: pattern, Handlers: nil})
 			}
-			buildRoutesMap(mu, pattern, ar, nnr, rt.Router)
-		} else if len(rt.Handlers) > 0 {
-			nr.Routes[pat] = DocRoute{
-				Pattern:  pat,
-				Handlers: rt.Handlers,
-				Router:   nil,
+
+			return nil
+		},
+		VisitHandlerFunc: func(s WalkState, dh DocHandler) error {
+			if route.subRouter() != nil {
+				return nil // the handlers of the sub-router are listed
 			}
 
-			// Remove the trailing slash if the handler is a subroute for "/"
-			routeKey := pattern
-			if pat == "/" && len(routeKey) > 1 {
-				routeKey = routeKey[:len(routeKey)-1]
+			pattern := routeKey(s.Pattern, route.Pattern)
+			if dh.Params == nil {
+				dh.Params = ParsePattern(pattern)
 			}
-			mu.Routes[routeKey] = copyDocRouter(*ar)
-		} else {
-			panic("not possible")
+
+			mr := &routes[indexes[pattern]]
+			mr.Handlers = append(mr.Handlers, MarkupHandler{
+				DocHandler: dh,
+				Chain:      s.Middlewares,
+				Pattern:    pattern,
+				TryItOut:   mu.Opts.TryItOut,
+			})
+
+			return nil
+		},
+		VisitMiddlewareFunc: nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Pattern < routes[j].Pattern })
+
+	anchors := map[string]bool{}
+	for i := range routes {
+		routes[i].ID = anchorID(anchors, routes[i].Pattern)
+	}
+
+	return routes, nil
+}
+
+// anchorID converts a route pattern into a unique HTML id.
+func anchorID(used map[string]bool, pattern string) string {
+	id := "route" + strings.Map(func(r rune) rune {
+		if ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
 		}
+
+		return '-'
+	}, pattern)
+
+	unique := id
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", id, i)
 	}
+	used[unique] = true
+
+	return unique
 }
 
 func (mu *MarkupDoc) githubSourceURL(file string, line int) string {
@@ -217,7 +270,8 @@ func (mu *MarkupDoc) githubSourceURL(file string, line int) string {
 	if mu.Opts.ProjectPath == "" {
 		return ""
 	}
-	for pkg, url := range mu.Opts.URLMap {
+	for _, pkg := range urlMapKeys(mu.Opts.URLMap) {
+		url := mu.Opts.URLMap[pkg]
 		if idx := strings.Index(file, pkg); idx >= 0 {
 			pos := idx + len(pkg)
 			url = strings.TrimRight(url, "/")
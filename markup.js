@@ -0,0 +1,109 @@
+// Explorer of the HTML page generated with MarkupOpts.Explorer:
+// search by pattern, filters by method and middleware, deep links.
+// Vanilla JS without dependencies, so the page works offline.
+(function () {
+  "use strict";
+
+  var doc = JSON.parse(document.getElementById("docgen-doc").textContent);
+  window.docgenDoc = doc;
+
+  var search = document.getElementById("docgen-search");
+  var methodsBox = document.getElementById("docgen-methods");
+  var middlewareSelect = document.getElementById("docgen-middleware");
+  var expand = document.getElementById("docgen-expand");
+  var count = document.getElementById("docgen-count");
+  var routes = Array.prototype.slice.call(document.querySelectorAll("section.route"));
+
+  // the Endpoints of the Doc list the methods and the effective middlewares
+  var methods = {};
+  var middlewares = {};
+  doc.endpoints.forEach(function (endpoint) {
+    methods[endpoint.method] = true;
+    endpoint.effective_middlewares.forEach(function (mw) { middlewares[mw.func] = true; });
+  });
+
+  var order = ["GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"];
+  Object.keys(methods).sort(function (a, b) {
+    var i = order.indexOf(a), j = order.indexOf(b);
+    if (i < 0) { i = order.length; }
+    if (j < 0) { j = order.length; }
+    return i - j || (a < b ? -1 : a > b ? 1 : 0);
+  }).forEach(function (method) {
+    var label = document.createElement("label");
+    var box = document.createElement("input");
+    box.type = "checkbox";
+    box.value = method;
+    box.checked = true;
+    box.addEventListener("change", filter);
+    label.appendChild(box);
+    label.appendChild(document.createTextNode(" " + method));
+    methodsBox.appendChild(label);
+  });
+
+  Object.keys(middlewares).sort().forEach(function (name) {
+    var option = document.createElement("option");
+    option.value = name;
+    option.textContent = name;
+    middlewareSelect.appendChild(option);
+  });
+
+  // filter hides the handlers and the routes not matching the search and the filters.
+  function filter() {
+    var text = search.value.trim().toLowerCase();
+    var mw = middlewareSelect.value;
+    var checked = {};
+    methodsBox.querySelectorAll("input:checked").forEach(function (box) { checked[box.value] = true; });
+
+    var shown = 0;
+    routes.forEach(function (section) {
+      var visible = 0;
+      if (section.querySelector("h3").textContent.toLowerCase().indexOf(text) >= 0) {
+        section.querySelectorAll(".handler").forEach(function (handler) {
+          var ok = checked[handler.dataset.method] === true &&
+            (mw === "" || handler.dataset.middlewares.split(" ").indexOf(mw) >= 0);
+          handler.hidden = !ok;
+          if (ok) {
+            visible++;
+          }
+        });
+      }
+      section.hidden = visible === 0;
+      var link = document.querySelector('.toc a[href="#' + section.id + '"]');
+      if (link) {
+        link.parentNode.hidden = section.hidden;
+      }
+      if (!section.hidden) {
+        shown++;
+      }
+    });
+    count.textContent = shown + " / " + routes.length + " routes";
+  }
+
+  // reveal resets the filters hiding the route of the URL fragment and opens its details.
+  function reveal() {
+    var section = location.hash.length > 1 && document.getElementById(decodeURIComponent(location.hash.slice(1)));
+    if (!section || !section.classList.contains("route")) {
+      return;
+    }
+    if (section.hidden) {
+      search.value = "";
+      middlewareSelect.value = "";
+      methodsBox.querySelectorAll("input").forEach(function (box) { box.checked = true; });
+      filter();
+    }
+    section.querySelectorAll("details").forEach(function (details) { details.open = true; });
+    section.scrollIntoView();
+  }
+
+  expand.addEventListener("click", function () {
+    var open = expand.textContent === "Expand all";
+    document.querySelectorAll("main details").forEach(function (details) { details.open = open; });
+    expand.textContent = open ? "Collapse all" : "Expand all";
+  });
+  search.addEventListener("input", filter);
+  middlewareSelect.addEventListener("change", filter);
+  window.addEventListener("hashchange", reveal);
+
+  filter();
+  reveal();
+})();
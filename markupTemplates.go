@@ -1,25 +1,144 @@
 package docgen
 
 import (
+	_ "embed" // markup.tmpl
+	"fmt"
+	"html"
+	"html/template"
 	"strconv"
 	"strings"
 )
 
-// BaseTemplate is a basic html page with placeholders for: {title}, {css}, {intro}, and {routes}.
-const BaseTemplate = ` <html>
+// Themes of MarkupOpts.Theme.
+const (
+	ThemeBass      = "bass"
+	ThemeMilligram = "milligram"
+)
+
+// MarkupData is the data of the "page" template, see MarkupTemplate.
+type MarkupData struct {
+	Title   string        // MarkupOpts.ProjectPath, "go-chi Docgen" when empty
+	Intro   template.HTML // MarkupOpts.Intro, trusted
+	CSS     template.CSS  // Theme, LayoutCSS and MarkupOpts.CSS
+	Favicon template.URL  // FaviconIcoData
+	Routes  []MarkupRoute // sorted by pattern
+
+	Diagram    string // Mermaid flowchart when MarkupOpts.Diagram is set
+	MermaidURL string // MarkupOpts.MermaidURL
+
+	Explorer bool        // MarkupOpts.Explorer
+	Script   template.JS // the explorer, when Explorer is true
+	Doc      Doc         // embedded as JSON by the explorer
+
+	TryItOut      bool        // MarkupOpts.TryItOut
+	ConsoleScript template.JS // the try-it-out console, when TryItOut is true
+}
+
+// MarkupRoute is a route of the HTML page, ID is its anchor.
+type MarkupRoute struct {
+	ID       string
+	Pattern  string
+	Handlers []MarkupHandler // sorted by MarkupOpts.MethodOrder
+}
+
+// MarkupHandler is a handler with its Chain of middlewares:
+// the middlewares of the routers, then the inline ones.
+type MarkupHandler struct {
+	DocHandler
+	Chain    []DocMiddleware
+	Pattern  string // full pattern of the route
+	TryItOut bool   // MarkupOpts.TryItOut
+}
+
+//go:embed markup.tmpl
+var markupTemplate string
+
+//go:embed markup.js
+var markupScript string
+
+//go:embed console.js
+var consoleScript string
+
+var defaultMarkupTemplate = template.Must(
+	template.New("markup.tmpl").Funcs((&MarkupDoc{}).funcs()).Parse(markupTemplate))
+
+// MarkupTemplate returns a copy of the default html/template templates of the HTML page:
+//
+//	page         the whole page, executed with a MarkupData
+//	toc          the table of contents (MarkupData)
+//	diagram      the Mermaid flowchart, when MarkupOpts.Diagram is set (MarkupData)
+//	routes       the list of the routes (MarkupData)
+//	route        a section per route (MarkupRoute)
+//	handler      a method with its middlewares and handler function (MarkupHandler)
+//	explorer     the search and filter controls, when MarkupOpts.Explorer is set (MarkupData)
+//	console      the try-it-out form, when MarkupOpts.TryItOut is set (MarkupHandler)
+//	func         a function linked to its source code (FuncInfo)
+//	annotations  the annotations of a handler (*Annotations)
+//
+// The text extracted from the source code, such as the comments, is escaped.
+// Redefine some templates to adapt the layout, e.g.
+//
+//	t := template.Must(docgen.MarkupTemplate().Parse(`{{define "toc"}}{{end}}`))
+//	page, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Template: t}}.Generate(r)
+//
+// The templates may call these funcs:
+//
+//	sourceURL file line  link to the source code, according to the MarkupOpts
+//	lower text           strings.ToLower
+//	trim text            strings.TrimSpace
+//	required bool        " (required)" when true
+//	join list separator  strings.Join
+func MarkupTemplate() *template.Template {
+	return template.Must(defaultMarkupTemplate.Clone())
+}
+
+// funcs returns the template funcs bound to the options of mu.
+func (mu *MarkupDoc) funcs() template.FuncMap {
+	return template.FuncMap{
+		"sourceURL": mu.githubSourceURL,
+		"lower":     strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"required":  required,
+		"join":      strings.Join,
+	}
+}
+
+// themeCSS returns the CSS kit of a MarkupOpts.Theme.
+func themeCSS(theme string) (string, error) {
+	switch theme {
+	case "", ThemeBass:
+		return BassCSS(), nil
+	case ThemeMilligram:
+		return MilligramMinCSS(), nil
+	default:
+		return "", fmt.Errorf("docgen: unknown HTML theme %q, want %q or %q", theme, ThemeBass, ThemeMilligram)
+	}
+}
+
+// BaseTemplate is a basic html page with placeholders for: {title}, {css}, {intro}, {toc} and {routes}.
+//
+// Deprecated: the HTML page is generated by the html/template of MarkupTemplate.
+const BaseTemplate = `<!DOCTYPE html>
+<html>
 <head>
+  <meta charset="utf-8" />
   <title>{title}</title>
   <style>{css}</style>
   <link rel="icon" type="image/png" href="{favicon.ico}" />
 </head>
 <body>
-  <h1>{title}</h1>
-  <div>
-    {intro}
-  </div>
-  <div>
-    {routes}
-  </div>        
+  <nav class="toc">
+    {toc}
+  </nav>
+  <main>
+    <h1>{title}</h1>
+    <div>
+      {intro}
+    </div>
+    <div>
+      {routes}
+    </div>
+  </main>
 </body>
 </html>
 `
@@ -57,6 +176,21 @@ func P(text string) string {
 	return "<p>" + text + "</p>"
 }
 
+// Anchor creates a link to href.
+func Anchor(href, text string) string {
+	return `<a href="` + html.EscapeString(href) + `">` + text + "</a>"
+}
+
+// Badge labels an HTTP method, colored by the "badge-<method>" CSS class.
+func Badge(method string) string {
+	return `<span class="badge badge-` + strings.ToLower(html.EscapeString(method)) + `">` + html.EscapeString(method) + "</span>"
+}
+
+// Details creates a collapsible block, closed by default.
+func Details(summary, text string) string {
+	return "<details><summary>" + summary + "</summary>" + text + "</details>"
+}
+
 // Head creates a header for a given level eg H1, H2, H3...
 func Head(level int, text string) string {
 	if strings.TrimSpace(text) == "" {
@@ -93,6 +227,13 @@ func MilligramMinCSS() string {
   `
 }
 
+// LayoutCSS styles the table of contents sidebar and the method badges.
+func LayoutCSS() string {
+	return `
+    .toc{position:fixed;top:0;bottom:0;left:0;width:18rem;overflow-y:auto;padding:1rem;border-right:1px solid #ccc;background-color:#fafafa}.toc ul{list-style:none;padding-left:0}.toc li{margin-bottom:.25em;font-size:.875rem;word-break:break-all}main{margin-left:18rem;padding:1rem 2rem}.route{border-top:1px solid #eee}.handler{margin:.5em 0 1em}.badge{display:inline-block;min-width:4.5em;padding:.125em .5em;border-radius:3px;color:#fff;background-color:#777;font-size:.75rem;font-weight:600;text-align:center}.badge-get{background-color:#2a7ae2}.badge-post{background-color:#2e9e4f}.badge-put{background-color:#d4880f}.badge-patch{background-color:#8e44ad}.badge-delete{background-color:#c0392b}details{margin-top:.25em}summary{cursor:pointer;color:#555}.deprecated{color:#c0392b}.explorer input,.explorer select{width:100%;margin-bottom:.5em}.explorer label{display:inline-block;margin-right:.5em;font-size:.75rem}.route:target{background-color:#fffbe6}.console label{display:block;margin-top:.25em;font-size:.875rem}.console input,.console textarea{width:100%}.console .response{white-space:pre-wrap;max-height:30em;overflow:auto}
+  `
+}
+
 // BassCSS is a zero config drop in css kit.
 func BassCSS() string {
 	return `
@@ -3,7 +3,9 @@ package docgen_test
 import (
 	"context"
 	"fmt"
+	"html/template"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -33,6 +35,227 @@ func TestMarkupRoutesDoc(t *testing.T) {
 	}
 }
 
+func TestMarkupRoutesDoc_page(t *testing.T) {
+	t.Parallel()
+
+	got := docgen.MarkupRoutesDoc(setupRouter(), docgen.MarkupOpts{
+		ProjectPath:        "github.com/teal-finance/docgen-yes",
+		Intro:              "<p>Generated by the tests</p>",
+		RouteText:          "",
+		ForceRelativeLinks: false,
+		URLMap:             map[string]string{},
+	})
+
+	for _, want := range []string{
+		"<title>github.com/teal-finance/docgen-yes</title>",
+		"<p>Generated by the tests</p>",
+		`<li><a href="#route-hubs--hubID--touch">/hubs/{hubID}/touch</a></li>`,
+		`<section class="route" id="route-hubs--hubID--touch">`,
+		`<span class="badge badge-get">GET</span>`,
+		"<details><summary>Middlewares (3)</summary>",
+		"Total # of routes: 14",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MarkupRoutesDoc() does not contain %q", want)
+		}
+	}
+
+	if strings.Contains(got, "](") {
+		t.Error("MarkupRoutesDoc() contains markdown link syntax")
+	}
+}
+
+func TestMarkupGenerator_escape(t *testing.T) {
+	t.Parallel()
+
+	dh := handler("GET", "Ping")
+	dh.Comment = "Ping replies <b>pong</b> & more."
+	dh.Annotations = &docgen.Annotations{Tags: []string{"<script>alert(1)</script>"}}
+
+	doc := docgen.Doc{Router: docgen.DocRouter{
+		Middlewares: []docgen.DocMiddleware{},
+		Routes:      docgen.DocRoutes{"/ping/{id:<x>}": {Handlers: docgen.DocHandlers{"GET": dh}}},
+	}}
+
+	b, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Intro: "<p>Trusted</p>"}}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := string(b)
+	for _, want := range []string{
+		"<title>go-chi Docgen</title>",
+		"<p>Trusted</p>",
+		"<p>Ping replies &lt;b&gt;pong&lt;/b&gt; &amp; more.</p>",
+		"<li>Tags: &lt;script&gt;alert(1)&lt;/script&gt;</li>",
+		">/ping/{id:&lt;x&gt;}</a>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %q, got:\n%s", want, page)
+		}
+	}
+
+	if strings.Contains(page, "<script>") || strings.Contains(page, "<b>") {
+		t.Errorf("the text from the source code should be escaped, got:\n%s", page)
+	}
+}
+
+func TestMarkupGenerator_theme(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		theme   string
+		css     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", theme: "", css: "", want: "-apple-system", wantErr: false},
+		{name: "bass", theme: docgen.ThemeBass, css: "", want: "-apple-system", wantErr: false},
+		{name: "milligram", theme: docgen.ThemeMilligram, css: "", want: "Milligram v1.3.0", wantErr: false},
+		{name: "user css", theme: "", css: ".badge-get{background-color:teal}", want: ".badge-get{background-color:teal}", wantErr: false},
+		{name: "unknown", theme: "dark", css: "", want: "", wantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Theme: c.theme, CSS: c.css}}.Generate(setupRouter())
+			if (err != nil) != c.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if !strings.Contains(string(b), c.want) {
+				t.Errorf("page should contain %q", c.want)
+			}
+		})
+	}
+}
+
+func TestMarkupGenerator_explorer(t *testing.T) {
+	t.Parallel()
+
+	b, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Explorer: true}}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := string(b)
+	for _, want := range []string{
+		`<input type="search" id="docgen-search"`,
+		`<div class="handler" data-method="GET" data-middlewares="github.com/teal-finance/docgen-yes_test.RequestID `,
+		"window.docgenDoc = doc;",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %q", want)
+		}
+	}
+
+	const start = `<script type="application/json" id="docgen-doc">`
+	_, embedded, ok := strings.Cut(page, start)
+	if !ok {
+		t.Fatalf("page should embed the Doc in %s", start)
+	}
+	embedded, _, _ = strings.Cut(embedded, "</script>")
+
+	doc, err := docgen.ParseJSON([]byte(embedded))
+	if err != nil {
+		t.Fatalf("ParseJSON(embedded Doc) error = %v", err)
+	}
+	if _, ok := doc.Router.Routes["/hubs/*"]; !ok {
+		t.Errorf("embedded Doc routes = %v", doc.Router.Routes.Patterns())
+	}
+
+	b, err = docgen.MarkupGenerator{Opts: docgen.MarkupOpts{}}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "<script") {
+		t.Error("the page without Explorer should not contain any script")
+	}
+}
+
+func TestMarkupGenerator_tryItOut(t *testing.T) {
+	t.Parallel()
+
+	dh := handler("POST", "CreateComment")
+	dh.Annotations = &docgen.Annotations{
+		Query:   []docgen.ParamAnnotation{{Name: "draft", Type: "bool", Required: false, Description: ""}},
+		Headers: []docgen.ParamAnnotation{{Name: "X-Request-Id", Type: "", Required: true, Description: ""}},
+		Body:    &docgen.BodyAnnotation{ContentType: "application/json", Type: "Comment", Description: "", Schema: nil},
+	}
+
+	doc := docgen.Doc{Router: docgen.DocRouter{
+		Middlewares: []docgen.DocMiddleware{},
+		Routes: docgen.DocRoutes{
+			"/articles/{month:[0-9]{2}}/*": {Handlers: docgen.DocHandlers{"POST": dh, "*": handler("*", "Any")}},
+		},
+	}}
+
+	b, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{TryItOut: true}}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := string(b)
+	for _, want := range []string{
+		`<form class="console" data-method="POST" data-pattern="/articles/{month:[0-9]{2}}/*" data-content-type="application/json">`,
+		`<input data-placeholder="{month:[0-9]{2}}" pattern="[0-9]{2}" required />`,
+		`<input data-placeholder="*" />`,
+		`<input data-query="draft" />`,
+		`<input data-header="X-Request-Id" required />`,
+		`<input name="method" value="GET" required />`,
+		`document.querySelectorAll("form.console")`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %q, got:\n%s", want, page)
+		}
+	}
+
+	b, err = docgen.MarkupGenerator{Opts: docgen.MarkupOpts{}}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "<form") {
+		t.Error("the console should be disabled by default")
+	}
+}
+
+func TestMarkupTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(docgen.MarkupTemplate().Parse(
+		`{{define "toc"}}<p>{{len .Routes}} routes</p>{{end}}` +
+			`{{define "route"}}<h3 id="{{.ID}}">{{.Pattern}}{{range .Handlers}} {{lower .Method}}{{end}}</h3>{{end}}`))
+
+	b, err := docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Template: tmpl}}.Generate(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := string(b)
+	for _, want := range []string{
+		"<p>14 routes</p>",
+		`<h3 id="route-hubs--hubID--view">/hubs/{hubID}/view get</h3>`,
+		"<title>go-chi Docgen</title>",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page should contain %q, got:\n%s", want, page)
+		}
+	}
+
+	if strings.Contains(page, "<section") {
+		t.Errorf("the route template should be redefined, got:\n%s", page)
+	}
+
+	tmpl = template.Must(docgen.MarkupTemplate().Parse(`{{define "toc"}}{{.Missing}}{{end}}`))
+	if _, err := (docgen.MarkupGenerator{Opts: docgen.MarkupOpts{Template: tmpl}}).Generate(setupRouter()); err == nil {
+		t.Error("Generate() should return the template error")
+	}
+}
+
 func TestMarkupDoc_String(t *testing.T) {
 	type fields struct {
 		Opts          docgen.MarkupOpts
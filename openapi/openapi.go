@@ -0,0 +1,542 @@
+// Package openapi converts the docgen routes documentation into an OpenAPI 3.1 document.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// Version is the OpenAPI Specification version of the generated documents.
+const Version = "3.1.0"
+
+// OpenAPI is the root object of an OpenAPI document.
+type OpenAPI struct {
+	OpenAPI    string      `json:"openapi"              yaml:"openapi"`
+	Info       Info        `json:"info"                 yaml:"info"`
+	Servers    []Server    `json:"servers,omitempty"    yaml:"servers,omitempty"`
+	Paths      Paths       `json:"paths"                yaml:"paths"`
+	Components *Components `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// Components holds the reusable objects referenced by the operations.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"         yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme defines a security scheme referenced by the Security of an Operation.
+type SecurityScheme struct {
+	Type         string `json:"type"                   yaml:"type"`
+	Scheme       string `json:"scheme,omitempty"       yaml:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"           yaml:"in,omitempty"`
+	Name         string `json:"name,omitempty"         yaml:"name,omitempty"`
+	Description  string `json:"description,omitempty"  yaml:"description,omitempty"`
+}
+
+// SecurityRequirement maps a security scheme name to the required scopes.
+type SecurityRequirement map[string][]string
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"                 yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version"               yaml:"version"`
+}
+
+// Server is a URL where the API is served.
+type Server struct {
+	URL         string `json:"url"                   yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Paths maps an OpenAPI path template (e.g. "/articles/{id}") to its operations.
+type Paths map[string]*PathItem
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"     yaml:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"     yaml:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"    yaml:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"  yaml:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty" yaml:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"    yaml:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"   yaml:"patch,omitempty"`
+	Trace   *Operation `json:"trace,omitempty"   yaml:"trace,omitempty"`
+
+	Parameters []Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Tags        []string              `json:"tags,omitempty"        yaml:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"     yaml:"summary,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"  yaml:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   Responses             `json:"responses"             yaml:"responses"`
+	Deprecated  bool                  `json:"deprecated,omitempty"  yaml:"deprecated,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"    yaml:"security,omitempty"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Content     map[string]MediaType `json:"content"               yaml:"content"`
+	Required    bool                 `json:"required,omitempty"    yaml:"required,omitempty"`
+}
+
+// MediaType describes the body of a given content type.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Responses maps an HTTP status code (or "default") to a Response.
+type Responses map[string]Response
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string               `json:"description"       yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string  `json:"name"                  yaml:"name"`
+	In          string  `json:"in"                    yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required"              yaml:"required"`
+	Schema      *Schema `json:"schema,omitempty"      yaml:"schema,omitempty"`
+}
+
+// Schema is the subset of JSON Schema used to describe parameters and bodies,
+// its $ref point to the Schemas of the Components.
+type Schema = docgen.Schema
+
+// SchemaRefPrefix is the prefix of the $ref pointing to the Schemas of the Components.
+const SchemaRefPrefix = "#/components/schemas/"
+
+// Options sets the top-level metadata of the generated document.
+type Options struct {
+	Title       string
+	Description string
+	Version     string
+	Servers     []Server
+
+	// SecuritySchemes declares the schemes of the @auth annotations,
+	// the "basic" and "bearer" HTTP schemes are declared when missing.
+	// The @auth annotations of the other undeclared schemes are left out
+	// of the security requirements, a valid document declaring all its schemes.
+	SecuritySchemes map[string]SecurityScheme
+
+	// SchemaDir is a directory of the Go module declaring the types of the @body
+	// and @response annotations, FromRouter converts them into Schemas when not empty.
+	SchemaDir string
+}
+
+// FromRouter builds the OpenAPI document of a chi router.
+func FromRouter(r chi.Routes, opts Options) (*OpenAPI, error) {
+	doc, err := docgen.BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SchemaDir != "" {
+		if err := docgen.ResolveSchemas(&doc, opts.SchemaDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return FromDoc(doc, opts), nil
+}
+
+// FromDoc converts a docgen.Doc into an OpenAPI document.
+// Each DocHandler becomes an Operation of the path built
+// by joining the patterns of its parent routers.
+// The Schemas of the doc, see docgen.ResolveSchemas, become the Schemas of the Components.
+func FromDoc(doc docgen.Doc, opts Options) *OpenAPI {
+	api := &OpenAPI{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       opts.Title,
+			Description: opts.Description,
+			Version:     opts.Version,
+		},
+		Servers: opts.Servers,
+		Paths:   Paths{},
+	}
+
+	if api.Info.Version == "" {
+		api.Info.Version = "0.0.0"
+	}
+
+	ids := map[string]int{}
+
+	var addRouter func(parentPattern string, dr docgen.DocRouter)
+	addRouter = func(parentPattern string, dr docgen.DocRouter) {
+		for _, pat := range sortedKeys(dr.Routes) {
+			rt := dr.Routes[pat]
+			pattern := joinPattern(parentPattern, pat)
+
+			if rt.Router != nil && len(rt.Router.Routes) > 0 {
+				addRouter(pattern, *rt.Router)
+			}
+
+			for _, method := range sortedKeys(rt.Handlers) {
+				dh := rt.Handlers[method]
+				path, params := pathTemplate(pattern)
+				item := api.Paths[path]
+				if item == nil {
+					item = &PathItem{}
+					api.Paths[path] = item
+				}
+
+				op := &Operation{
+					OperationID: baseOperationID(dh.Func, method, path), // made unique by set
+					Summary:     summary(dh.Comment),
+					Description: strings.TrimSpace(dh.Comment),
+					Parameters:  params,
+					Responses: Responses{
+						"default": {Description: "Default response", Content: nil},
+					},
+				}
+
+				if dh.Annotations != nil {
+					api.annotate(op, dh.Annotations, opts.SecuritySchemes)
+				}
+
+				item.set(method, op, ids)
+			}
+		}
+	}
+
+	addRouter("", doc.Router)
+
+	for name, schema := range doc.Schemas {
+		api.schemas()[name] = componentSchema(schema)
+	}
+
+	for name, scheme := range opts.SecuritySchemes {
+		api.securitySchemes()[name] = scheme
+	}
+
+	return api
+}
+
+// annotate completes the operation with the annotations of the handler comment,
+// the security requirements being limited to the declared schemes.
+func (api *OpenAPI) annotate(op *Operation, ann *docgen.Annotations, declared map[string]SecurityScheme) {
+	op.Tags = ann.Tags
+	op.Deprecated = ann.Deprecated != nil
+
+	for _, p := range ann.Params {
+		for i := range op.Parameters {
+			if op.Parameters[i].Name == p.Name {
+				op.Parameters[i].Description = p.Description
+				op.Parameters[i].Schema.Type = p.JSONType()
+			}
+		}
+	}
+
+	for _, p := range ann.Query {
+		op.Parameters = append(op.Parameters, parameter("query", p))
+	}
+
+	for _, p := range ann.Headers {
+		op.Parameters = append(op.Parameters, parameter("header", p))
+	}
+
+	if b := ann.Body; b != nil {
+		op.RequestBody = &RequestBody{
+			Description: b.Description,
+			Content:     map[string]MediaType{b.ContentType: {Schema: typeSchema(b.Type, b.Schema)}},
+			Required:    true,
+		}
+	}
+
+	if len(ann.Responses) > 0 {
+		op.Responses = Responses{}
+	}
+	for _, r := range ann.Responses {
+		resp := Response{Description: r.Description, Content: nil}
+		if resp.Description == "" {
+			resp.Description = http.StatusText(r.Status)
+		}
+		if r.ContentType != "" {
+			resp.Content = map[string]MediaType{r.ContentType: {Schema: typeSchema(r.Type, r.Schema)}}
+		}
+		op.Responses[strconv.Itoa(r.Status)] = resp
+	}
+
+	for _, a := range ann.Auth {
+		if _, ok := declared[a.Scheme]; !ok {
+			if a.Scheme != "basic" && a.Scheme != "bearer" {
+				continue // undeclared scheme
+			}
+
+			schemes := api.securitySchemes()
+			if _, ok := schemes[a.Scheme]; !ok {
+				schemes[a.Scheme] = SecurityScheme{Type: "http", Scheme: a.Scheme}
+			}
+		}
+
+		op.Security = append(op.Security, SecurityRequirement{a.Scheme: a.Scopes})
+	}
+}
+
+func (api *OpenAPI) securitySchemes() map[string]SecurityScheme {
+	if api.Components == nil {
+		api.Components = &Components{Schemas: nil, SecuritySchemes: nil}
+	}
+	if api.Components.SecuritySchemes == nil {
+		api.Components.SecuritySchemes = map[string]SecurityScheme{}
+	}
+
+	return api.Components.SecuritySchemes
+}
+
+func parameter(in string, p docgen.ParamAnnotation) Parameter {
+	return Parameter{
+		Name:        p.Name,
+		In:          in,
+		Description: p.Description,
+		Required:    p.Required,
+		Schema:      schema(p.JSONType(), "", ""),
+	}
+}
+
+func (api *OpenAPI) schemas() map[string]*Schema {
+	if api.Components == nil {
+		api.Components = &Components{Schemas: nil, SecuritySchemes: nil}
+	}
+	if api.Components.Schemas == nil {
+		api.Components.Schemas = map[string]*Schema{}
+	}
+
+	return api.Components.Schemas
+}
+
+// typeSchema describes a body by its resolved schema,
+// or by the name of its Go type when unresolved.
+func typeSchema(typ string, resolved *Schema) *Schema {
+	if resolved != nil {
+		return componentSchema(resolved)
+	}
+
+	return schema("object", typ, "")
+}
+
+// componentSchema copies a schema, pointing its $ref to the Components.
+func componentSchema(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	if c.Ref != "" {
+		c.Ref = SchemaRefPrefix + strings.TrimPrefix(c.Ref, docgen.SchemaRefPrefix)
+	}
+
+	if s.Properties != nil {
+		c.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, p := range s.Properties {
+			c.Properties[name] = componentSchema(p)
+		}
+	}
+
+	c.Items = componentSchema(s.Items)
+	c.AdditionalProperties = componentSchema(s.AdditionalProperties)
+
+	return &c
+}
+
+func schema(typ, title, pattern string) *Schema {
+	return &Schema{
+		Ref:                  "",
+		Type:                 typ,
+		Format:               "",
+		Title:                title,
+		Pattern:              pattern,
+		Properties:           nil,
+		Required:             nil,
+		Items:                nil,
+		AdditionalProperties: nil,
+	}
+}
+
+// Generator implements docgen.DocGenerator, e.g. for docgen.HandlerOpts.Formats.
+type Generator struct {
+	Opts Options
+	YAML bool // YAML instead of JSON
+}
+
+// GenerateDoc implements docgen.DocGenerator.
+func (g Generator) GenerateDoc(doc docgen.Doc) ([]byte, error) {
+	api := FromDoc(doc, g.Opts)
+	if g.YAML {
+		return api.YAML()
+	}
+
+	return api.JSON()
+}
+
+// JSON serializes the OpenAPI document as indented JSON.
+func (api *OpenAPI) JSON() ([]byte, error) {
+	return json.MarshalIndent(api, "", "  ")
+}
+
+// YAML serializes the OpenAPI document as YAML.
+func (api *OpenAPI) YAML() ([]byte, error) {
+	return yaml.Marshal(api)
+}
+
+// set stores the operation under the given HTTP method, with a unique operationId among ids.
+// The chi wildcard method "*" is expanded to all methods not already set,
+// each with the operationId suffixed by the method.
+func (item *PathItem) set(method string, op *Operation, ids map[string]int) {
+	if method == "*" {
+		for _, m := range methods {
+			if *item.operation(m) == nil {
+				opCopy := *op
+				opCopy.OperationID = operationID(ids, op.OperationID+"_"+strings.ToLower(m))
+				*item.operation(m) = &opCopy
+			}
+		}
+
+		return
+	}
+
+	if ptr := item.operation(method); ptr != nil {
+		op.OperationID = operationID(ids, op.OperationID)
+		*ptr = op
+	}
+}
+
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+func (item *PathItem) operation(method string) **Operation {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return &item.Get
+	case http.MethodPut:
+		return &item.Put
+	case http.MethodPost:
+		return &item.Post
+	case http.MethodDelete:
+		return &item.Delete
+	case http.MethodOptions:
+		return &item.Options
+	case http.MethodHead:
+		return &item.Head
+	case http.MethodPatch:
+		return &item.Patch
+	case http.MethodTrace:
+		return &item.Trace
+	default:
+		return nil // CONNECT and custom methods are not supported by OpenAPI
+	}
+}
+
+// joinPattern concatenates a sub-route pattern to its mount point,
+// the root "/" of a sub-router documents the mount point itself.
+func joinPattern(parentPattern, pattern string) string {
+	if parentPattern != "" && pattern == "/" {
+		return strings.TrimSuffix(parentPattern, "/*")
+	}
+
+	return docgen.JoinPattern(parentPattern, pattern)
+}
+
+// pathTemplate converts a chi pattern into an OpenAPI path template
+// and extracts its path parameters:
+// "/{id}" and "/{id:[0-9]+}" become "/{id}" and a trailing "*" becomes "{wildcard}".
+func pathTemplate(pattern string) (string, []Parameter) {
+	var params []Parameter
+
+	for _, p := range docgen.ParsePattern(pattern) {
+		param := Parameter{
+			Name:        p.Name,
+			In:          "path",
+			Description: "",
+			Required:    true,
+			Schema:      schema("string", "", anchored(p.Regex)),
+		}
+		if p.CatchAll {
+			param.Name = "wildcard"
+			param.Description = "Catch-all remainder of the path"
+		}
+
+		pattern = strings.Replace(pattern, p.Placeholder(), "{"+param.Name+"}", 1)
+		params = append(params, param)
+	}
+
+	return pattern, params
+}
+
+// anchored returns the regexp anchored like chi does when matching a path segment.
+func anchored(regex string) string {
+	if regex == "" {
+		return ""
+	}
+	if regex[0] != '^' {
+		regex = "^" + regex
+	}
+	if regex[len(regex)-1] != '$' {
+		regex += "$"
+	}
+
+	return regex
+}
+
+// baseOperationID returns the operationId derived from the handler function name,
+// or from the method and the path of an anonymous handler.
+func baseOperationID(fn, method, path string) string {
+	if fn == "" {
+		return strings.ToLower(method) + path
+	}
+
+	return fn
+}
+
+// operationID registers id in ids and returns it,
+// suffixed by "_2", "_3"... when already registered.
+func operationID(ids map[string]int, id string) string {
+	unique := id
+	for n := 2; ids[unique] > 0; n++ {
+		unique = id + "_" + strconv.Itoa(n)
+	}
+	ids[unique]++
+
+	return unique
+}
+
+// summary returns the first sentence of a handler comment.
+func summary(comment string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(comment), "\n")
+
+	return strings.TrimSpace(line)
+}
+
+// sortedKeys returns the keys of a DocRoutes or DocHandlers map in lexical order
+// so the operationId deduplication is stable between runs.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
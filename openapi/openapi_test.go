@@ -0,0 +1,323 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/openapi"
+	"github.com/teal-finance/docgen-yes/testdata/articles"
+)
+
+// ListArticles returns an array of Articles.
+func ListArticles(w http.ResponseWriter, r *http.Request) {}
+
+// GetArticle returns a specific Article.
+// The Article is loaded from the URL parameter.
+// @param articleID int Identifier of the Article
+// @query fields string Comma-separated list of fields
+// @response 200 application/json Article
+// @response 404
+// @tag articles
+// @auth bearer
+func GetArticle(w http.ResponseWriter, r *http.Request) {}
+
+// Archive lists the Articles of a given month.
+func Archive(w http.ResponseWriter, r *http.Request) {}
+
+func Router() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+	r.Route("/articles", func(r chi.Router) {
+		r.Get("/", ListArticles)
+		r.Get("/{articleID}", GetArticle)
+		r.Get("/{month:[0-9]{2}}/archive", Archive)
+	})
+	r.Mount("/files", http.StripPrefix("/files", http.FileServer(http.Dir("."))))
+
+	return r
+}
+
+func TestFromRouter(t *testing.T) {
+	t.Parallel()
+
+	api, err := openapi.FromRouter(Router(), openapi.Options{
+		Title:       "Articles",
+		Description: "",
+		Version:     "v1",
+		Servers:     nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if api.OpenAPI != openapi.Version {
+		t.Errorf("OpenAPI = %q, want %q", api.OpenAPI, openapi.Version)
+	}
+
+	cases := []struct {
+		path        string
+		operationID string
+		summary     string
+		params      []openapi.Parameter
+	}{{
+		path:        "/articles",
+		operationID: "ListArticles",
+		summary:     "ListArticles returns an array of Articles.",
+		params:      nil,
+	}, {
+		path:        "/articles/{articleID}",
+		operationID: "GetArticle",
+		summary:     "GetArticle returns a specific Article.",
+		params: []openapi.Parameter{{
+			Name:        "articleID",
+			In:          "path",
+			Description: "Identifier of the Article",
+			Required:    true,
+			Schema:      &openapi.Schema{Type: "integer", Title: "", Pattern: ""},
+		}, {
+			Name:        "fields",
+			In:          "query",
+			Description: "Comma-separated list of fields",
+			Required:    false,
+			Schema:      &openapi.Schema{Type: "string", Title: "", Pattern: ""},
+		}},
+	}, {
+		path:        "/articles/{month}/archive",
+		operationID: "Archive",
+		summary:     "Archive lists the Articles of a given month.",
+		params: []openapi.Parameter{{
+			Name:        "month",
+			In:          "path",
+			Description: "",
+			Required:    true,
+			Schema:      &openapi.Schema{Type: "string", Title: "", Pattern: "^[0-9]{2}$"},
+		}},
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.path, func(t *testing.T) {
+			t.Parallel()
+
+			item, ok := api.Paths[c.path]
+			if !ok || item.Get == nil {
+				t.Fatalf("missing GET %s in %v", c.path, api.Paths)
+			}
+
+			if item.Get.OperationID != c.operationID {
+				t.Errorf("operationId = %q, want %q", item.Get.OperationID, c.operationID)
+			}
+			if item.Get.Summary != c.summary {
+				t.Errorf("summary = %q, want %q", item.Get.Summary, c.summary)
+			}
+
+			got, _ := json.Marshal(item.Get.Parameters)
+			want, _ := json.Marshal(c.params)
+			if string(got) != string(want) {
+				t.Errorf("parameters = %s, want %s", got, want)
+			}
+		})
+	}
+
+	get := api.Paths["/articles/{articleID}"].Get
+	if get.Responses["200"].Content["application/json"].Schema.Title != "Article" {
+		t.Errorf("responses = %v, want a 200 Article", get.Responses)
+	}
+	if get.Responses["404"].Description != "Not Found" {
+		t.Errorf("responses = %v, want a 404", get.Responses)
+	}
+	if len(get.Security) != 1 || api.Components.SecuritySchemes["bearer"].Scheme != "bearer" {
+		t.Errorf("security = %v, components = %v, want bearer", get.Security, api.Components)
+	}
+
+	if item := api.Paths["/files/{wildcard}"]; item == nil || item.Get == nil || item.Post == nil {
+		t.Errorf("mounted handler should document all methods of /files/{wildcard}, got %v", api.Paths)
+	}
+}
+
+func TestFromRouter_schemas(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Post("/articles", articles.CreateArticle)
+
+	_, err := openapi.FromRouter(r, openapi.Options{
+		Title:           "Articles",
+		Description:     "",
+		Version:         "",
+		Servers:         nil,
+		SecuritySchemes: nil,
+		SchemaDir:       "..",
+	})
+	if err == nil {
+		t.Fatal("the unknown type Missing should be reported")
+	}
+
+	doc, err := docgen.BuildDoc(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = docgen.ResolveSchemas(&doc, "..")
+
+	api := openapi.FromDoc(doc, openapi.Options{
+		Title:           "Articles",
+		Description:     "",
+		Version:         "",
+		Servers:         nil,
+		SecuritySchemes: nil,
+		SchemaDir:       "",
+	})
+
+	post := api.Paths["/articles"].Post
+	if ref := post.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/ArticleRequest" {
+		t.Errorf("requestBody $ref = %q, want ArticleRequest", ref)
+	}
+	if ref := post.Responses["201"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Article" {
+		t.Errorf("201 $ref = %q, want Article", ref)
+	}
+	if title := post.Responses["400"].Content["application/json"].Schema.Title; title != "Missing" {
+		t.Errorf("unresolved type should keep its name as title, got %q", title)
+	}
+
+	article := api.Components.Schemas["Article"]
+	if article == nil {
+		t.Fatalf("missing Article in components %v", api.Components.Schemas)
+	}
+	if ref := article.Properties["related"].Items.Ref; ref != "#/components/schemas/Article" {
+		t.Errorf("nested $ref = %q, want it in the components", ref)
+	}
+}
+
+func TestFromDoc_security(t *testing.T) {
+	t.Parallel()
+
+	_, ann := docgen.ParseAnnotations("Secured.\n@auth bearer\n@auth apikey\n@auth oauth2 articles:read")
+	doc := docgen.Doc{
+		Router: docgen.DocRouter{
+			Middlewares: []docgen.DocMiddleware{},
+			Routes: docgen.DocRoutes{
+				"/secured": {Handlers: docgen.DocHandlers{"GET": {
+					Middlewares: []docgen.DocMiddleware{},
+					Method:      "GET",
+					Params:      nil,
+					FuncInfo:    docgen.FuncInfo{Pkg: "example.com/api", Func: "Secured", Annotations: ann},
+				}}},
+			},
+		},
+		Schemas: nil,
+	}
+
+	api := openapi.FromDoc(doc, openapi.Options{
+		Title:       "Secured",
+		Description: "",
+		Version:     "",
+		Servers:     nil,
+		SecuritySchemes: map[string]openapi.SecurityScheme{
+			"oauth2": {Type: "oauth2", Scheme: "", BearerFormat: "", In: "", Name: "", Description: ""},
+		},
+		SchemaDir: "",
+	})
+
+	got, _ := json.Marshal(api.Paths["/secured"].Get.Security)
+	if want := `[{"bearer":[]},{"oauth2":["articles:read"]}]`; string(got) != want {
+		t.Errorf("security = %s, want %s without the undeclared apikey", got, want)
+	}
+
+	for name := range api.Components.SecuritySchemes {
+		if name != "bearer" && name != "oauth2" {
+			t.Errorf("unexpected security scheme %q", name)
+		}
+	}
+	if len(api.Components.SecuritySchemes) != 2 {
+		t.Errorf("securitySchemes = %v, want bearer and oauth2", api.Components.SecuritySchemes)
+	}
+}
+
+func TestFromDoc_operationIDs(t *testing.T) {
+	t.Parallel()
+
+	handlers := func(method, fn string) docgen.DocHandlers {
+		return docgen.DocHandlers{method: {
+			Middlewares: []docgen.DocMiddleware{},
+			Method:      method,
+			Params:      nil,
+			FuncInfo:    docgen.FuncInfo{Pkg: "example.com/api", Func: fn},
+		}}
+	}
+
+	doc := docgen.Doc{
+		Router: docgen.DocRouter{
+			Middlewares: []docgen.DocMiddleware{},
+			Routes: docgen.DocRoutes{
+				"/a": {Handlers: handlers("GET", "Proxy_post")},
+				"/b": {Handlers: handlers("*", "Proxy")}, // expanded to Proxy_get, Proxy_post...
+				"/c": {Handlers: handlers("GET", "Proxy_get")},
+			},
+		},
+		Schemas: nil,
+	}
+
+	api := openapi.FromDoc(doc, openapi.Options{
+		Title:           "Proxy",
+		Description:     "",
+		Version:         "",
+		Servers:         nil,
+		SecuritySchemes: nil,
+		SchemaDir:       "",
+	})
+
+	cases := []struct {
+		op   *openapi.Operation
+		want string
+	}{
+		{api.Paths["/a"].Get, "Proxy_post"},
+		{api.Paths["/b"].Get, "Proxy_get"},
+		{api.Paths["/b"].Post, "Proxy_post_2"},
+		{api.Paths["/c"].Get, "Proxy_get_2"},
+	}
+
+	for _, c := range cases {
+		if c.op == nil {
+			t.Fatalf("missing operation %q in %v", c.want, api.Paths)
+		}
+		if c.op.OperationID != c.want {
+			t.Errorf("operationId = %q, want %q", c.op.OperationID, c.want)
+		}
+	}
+}
+
+func TestOpenAPI_YAML(t *testing.T) {
+	t.Parallel()
+
+	doc, err := docgen.BuildDoc(Router())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := openapi.FromDoc(doc, openapi.Options{
+		Title:       "Articles",
+		Description: "",
+		Version:     "",
+		Servers:     nil,
+	})
+
+	b, err := api.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]any
+	if err := yaml.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded["openapi"] != openapi.Version {
+		t.Errorf("openapi = %v, want %v", decoded["openapi"], openapi.Version)
+	}
+}
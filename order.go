@@ -0,0 +1,70 @@
+package docgen
+
+import "sort"
+
+// DefaultMethodOrder is the order of the HTTP methods in the generated documentation,
+// the methods not listed follow in alphabetical order, then "*" (all methods).
+var DefaultMethodOrder = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"}
+
+// Patterns returns the route patterns in alphabetical order,
+// the generators range over them instead of the map so their output is stable.
+func (drts DocRoutes) Patterns() []string {
+	patterns := make([]string, 0, len(drts))
+	for pat := range drts {
+		patterns = append(patterns, pat)
+	}
+	sort.Strings(patterns)
+
+	return patterns
+}
+
+// Methods returns the methods of the handlers sorted according to order,
+// DefaultMethodOrder when nil.
+func (dhs DocHandlers) Methods(order []string) []string {
+	if order == nil {
+		order = DefaultMethodOrder
+	}
+
+	rank := make(map[string]int, len(order)+1)
+	for i, method := range order {
+		rank[method] = i - len(order) // before the methods not listed (rank 0)
+	}
+	if _, ok := rank["*"]; !ok {
+		rank["*"] = 1
+	}
+
+	methods := make([]string, 0, len(dhs))
+	for method := range dhs {
+		methods = append(methods, method)
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		ri, rj := rank[methods[i]], rank[methods[j]]
+		if ri != rj {
+			return ri < rj
+		}
+
+		return methods[i] < methods[j]
+	})
+
+	return methods
+}
+
+// urlMapKeys returns the package paths of a URLMap, the longest first,
+// so the most specific path is the one linked whatever the map order.
+func urlMapKeys(urlMap map[string]string) []string {
+	keys := make([]string, 0, len(urlMap))
+	for pkg := range urlMap {
+		keys = append(keys, pkg)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
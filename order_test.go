@@ -0,0 +1,97 @@
+package docgen_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestDocHandlers_Methods(t *testing.T) {
+	t.Parallel()
+
+	handlers := docgen.DocHandlers{}
+	for _, m := range []string{"*", "PROPFIND", "DELETE", "POST", "GET", "MKCOL", "OPTIONS"} {
+		handlers[m] = docgen.DocHandler{}
+	}
+
+	cases := []struct {
+		name  string
+		order []string
+		want  []string
+	}{{
+		name:  "default",
+		order: nil,
+		want:  []string{"GET", "POST", "DELETE", "OPTIONS", "MKCOL", "PROPFIND", "*"},
+	}, {
+		name:  "alphabetical",
+		order: []string{},
+		want:  []string{"DELETE", "GET", "MKCOL", "OPTIONS", "POST", "PROPFIND", "*"},
+	}, {
+		name:  "custom",
+		order: []string{"PROPFIND", "POST"},
+		want:  []string{"PROPFIND", "POST", "DELETE", "GET", "MKCOL", "OPTIONS", "*"},
+	}, {
+		name:  "all methods first",
+		order: []string{"*", "GET"},
+		want:  []string{"*", "GET", "DELETE", "MKCOL", "OPTIONS", "POST", "PROPFIND"},
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := handlers.Methods(c.order); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Methods() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDocRoutes_Patterns(t *testing.T) {
+	t.Parallel()
+
+	routes := docgen.DocRoutes{"/b": {}, "/a/*": {}, "/": {}, "/a": {}}
+
+	want := []string{"/", "/a", "/a/*", "/b"}
+	if got := routes.Patterns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Patterns() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerators_deterministic(t *testing.T) {
+	t.Parallel()
+
+	r := setupRouter()
+
+	generators := map[string]docgen.Generator{
+		"json":     docgen.JSONGenerator{},
+		"markdown": docgen.MarkdownGenerator{Opts: docgen.MarkdownOpts{}},
+		"html":     docgen.MarkupGenerator{Opts: docgen.MarkupOpts{}},
+	}
+
+	for name, g := range generators {
+		name, g := name, g
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			first, err := g.Generate(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 20; i++ {
+				got, err := g.Generate(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != string(first) {
+					t.Fatalf("run %d differs from the first one", i+1)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package docgen
+
+import "strings"
+
+// DocParam describes a URL parameter of a chi route pattern,
+// e.g. "{articleID}", "{month:[0-9]{2}}" or the catch-all "*".
+type DocParam struct {
+	Name     string `json:"name"`
+	Regex    string `json:"regex,omitempty"`
+	Position int    `json:"position"` // ordinal among the parameters of the pattern, from 0
+	CatchAll bool   `json:"catch_all,omitempty"`
+}
+
+// Placeholder returns the chi syntax of the parameter as written in the pattern.
+func (p DocParam) Placeholder() string {
+	switch {
+	case p.CatchAll:
+		return "*"
+	case p.Regex != "":
+		return "{" + p.Name + ":" + p.Regex + "}"
+	default:
+		return "{" + p.Name + "}"
+	}
+}
+
+// ParsePattern extracts the URL parameters of a chi route pattern.
+// The parsing stops at a brace without closing brace, a pattern chi rejects.
+func ParsePattern(pattern string) []DocParam {
+	params := []DocParam{}
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			end := closingBrace(pattern, i)
+			if end < 0 {
+				return params
+			}
+			name, regex, _ := strings.Cut(pattern[i+1:end], ":")
+			params = append(params, DocParam{
+				Name:     name,
+				Regex:    regex,
+				Position: len(params),
+				CatchAll: false,
+			})
+			i = end
+		case '*':
+			params = append(params, DocParam{
+				Name:     "*",
+				Regex:    "",
+				Position: len(params),
+				CatchAll: true,
+			})
+		}
+	}
+
+	return params
+}
+
+// JoinPattern concatenates a route pattern to the pattern of its parent router,
+// removing the "/*" chi appends to the mount point of a sub-router.
+func JoinPattern(parentPattern, pattern string) string {
+	return strings.TrimSuffix(parentPattern, "/*") + pattern
+}
+
+// closingBrace returns the index of the brace closing the one at index start,
+// taking into account the nested braces of regexp quantifiers like {2}, or -1 if none.
+func closingBrace(pattern string, start int) int {
+	depth := 0
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,91 @@
+package docgen_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func TestParsePattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		pattern string
+		want    []docgen.DocParam
+	}{{
+		name:    "static",
+		pattern: "/articles",
+		want:    []docgen.DocParam{},
+	}, {
+		name:    "named",
+		pattern: "/articles/{articleID}",
+		want: []docgen.DocParam{
+			{Name: "articleID", Regex: "", Position: 0, CatchAll: false},
+		},
+	}, {
+		name:    "regex with quantifier",
+		pattern: "/{month:[0-9]{2}}/{slug:[a-z-]+}",
+		want: []docgen.DocParam{
+			{Name: "month", Regex: "[0-9]{2}", Position: 0, CatchAll: false},
+			{Name: "slug", Regex: "[a-z-]+", Position: 1, CatchAll: false},
+		},
+	}, {
+		name:    "catch-all",
+		pattern: "/hubs/{hubID}/view/*",
+		want: []docgen.DocParam{
+			{Name: "hubID", Regex: "", Position: 0, CatchAll: false},
+			{Name: "*", Regex: "", Position: 1, CatchAll: true},
+		},
+	}, {
+		name:    "unclosed brace",
+		pattern: "/{hubID}/{",
+		want: []docgen.DocParam{
+			{Name: "hubID", Regex: "", Position: 0, CatchAll: false},
+		},
+	}, {
+		name:    "unclosed regex",
+		pattern: "/{month:[0-9]{2}",
+		want:    []docgen.DocParam{},
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := docgen.ParsePattern(c.pattern)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("ParsePattern() = %v, want %v", got, c.want)
+			}
+
+			for _, p := range got {
+				if !strings.Contains(c.pattern, p.Placeholder()) {
+					t.Errorf("Placeholder() = %q not found in %q", p.Placeholder(), c.pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildDoc_params(t *testing.T) {
+	t.Parallel()
+
+	doc, err := docgen.BuildDoc(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hubs := doc.Router.Routes["/hubs/*"].Router.Routes["/{hubID}/*"]
+	if want := []docgen.DocParam{{Name: "hubID", Regex: "", Position: 0, CatchAll: false}}; !reflect.DeepEqual(hubs.Params, want) {
+		t.Errorf("DocRoute.Params = %v, want %v", hubs.Params, want)
+	}
+
+	touch := hubs.Router.Routes["/touch"].Handlers["GET"]
+	if want := []docgen.DocParam{{Name: "hubID", Regex: "", Position: 0, CatchAll: false}}; !reflect.DeepEqual(touch.Params, want) {
+		t.Errorf("DocHandler.Params = %v, want %v", touch.Params, want)
+	}
+}
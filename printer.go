@@ -0,0 +1,330 @@
+package docgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RoutePrinter prints the routes of a router as text, like the tree command
+// or `rails routes`. The zero value prints the full patterns to os.Stdout,
+// one per line, as PrintRoutes: in the order of chi for a router,
+// whose Doc is only built to print the handlers or the tree.
+type RoutePrinter struct {
+	// Writer receives the routes, os.Stdout when nil.
+	Writer io.Writer
+
+	// Tree draws the tree of the routers with their relative patterns,
+	// instead of the flat list of the full patterns, such as "/articles/*/{id}".
+	Tree bool
+
+	// Methods, Handlers and Middlewares add the columns of the method,
+	// of the handler Func and file:line, and of the middlewares to each handler line.
+	// The flat list shows the complete middleware chain, the tree the middlewares
+	// of each router on its line and the inline middlewares on the handler line.
+	Methods     bool
+	Handlers    bool
+	Middlewares bool
+
+	// Color colors the methods and dims the tree, the sources and the middlewares
+	// with ANSI escape codes, for a terminal.
+	Color bool
+
+	// Align pads the columns to the same width.
+	Align bool
+
+	// MethodOrder sorts the methods of a route, DefaultMethodOrder when nil.
+	MethodOrder []string
+}
+
+// Generate implements Generator.
+func (p RoutePrinter) Generate(r chi.Routes) ([]byte, error) {
+	if r == nil {
+		return nil, errors.New("docgen: router is nil")
+	}
+
+	if !p.Tree && !p.detailed() {
+		return p.format(p.patterns(nil, "", r)), nil
+	}
+
+	doc, err := BuildDoc(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.GenerateDoc(doc)
+}
+
+// GenerateDoc implements DocGenerator.
+func (p RoutePrinter) GenerateDoc(doc Doc) ([]byte, error) {
+	var rows []printRow
+
+	if p.Tree {
+		rows = append(rows, printRow{
+			{prefix: "", text: "/", color: ""},
+			{prefix: "", text: "", color: ""},
+			{prefix: "", text: "", color: ""},
+			{prefix: "", text: "", color: ""},
+			p.middlewares(doc.Router.Middlewares),
+		})
+		rows = p.tree(rows, "", doc.Router)
+	} else {
+		rows = p.flat(rows, "", doc.Router, []DocMiddleware{})
+	}
+
+	return p.format(rows), nil
+}
+
+// Print prints the routes of the router r to the Writer.
+func (p RoutePrinter) Print(r chi.Routes) error {
+	b, err := p.Generate(r)
+	if err != nil {
+		return err
+	}
+
+	return p.write(b)
+}
+
+// PrintDoc prints the routes of the Doc to the Writer.
+func (p RoutePrinter) PrintDoc(doc Doc) error {
+	b, err := p.GenerateDoc(doc)
+	if err != nil {
+		return err
+	}
+
+	return p.write(b)
+}
+
+func (p RoutePrinter) write(b []byte) error {
+	w := p.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("docgen: printing the routes: %w", err)
+	}
+
+	return nil
+}
+
+// printRow is a line of the RoutePrinter: pattern, method, handler, source and middlewares.
+type printRow [5]printCell
+
+type printCell struct {
+	prefix string // branches of the tree
+	text   string
+	color  string // ANSI code of the text
+}
+
+// Colors of the RoutePrinter.
+const (
+	ansiDim     = "2"
+	ansiRed     = "31"
+	ansiGreen   = "32"
+	ansiYellow  = "33"
+	ansiBlue    = "34"
+	ansiMagenta = "35"
+)
+
+// detailed reports whether each handler has its own line.
+func (p RoutePrinter) detailed() bool {
+	return p.Methods || p.Handlers || p.Middlewares
+}
+
+// patterns appends the rows of the full patterns of the routes of r in the order of chi,
+// the plain listing does not need the handlers of the Doc.
+func (p RoutePrinter) patterns(rows []printRow, parentPattern string, r chi.Routes) []printRow {
+	for _, rt := range r.Routes() {
+		if rt.SubRoutes != nil {
+			rows = p.patterns(rows, parentPattern+rt.Pattern, rt.SubRoutes)
+
+			continue
+		}
+
+		rows = append(rows, printRow{{prefix: "", text: parentPattern + rt.Pattern, color: ""}})
+	}
+
+	return rows
+}
+
+// flat appends the rows of the routes of the router mounted on the parent pattern,
+// concatenated as PrintRoutes does, after the middlewares of the parent routers.
+func (p RoutePrinter) flat(rows []printRow, parentPattern string, dr DocRouter, middlewares []DocMiddleware) []printRow {
+	middlewares = append(middlewares[:len(middlewares):len(middlewares)], dr.middlewares()...)
+
+	for _, pat := range dr.Routes.Patterns() {
+		rt := dr.Routes[pat]
+		pattern := parentPattern + pat
+
+		if sr := rt.subRouter(); sr != nil {
+			rows = p.flat(rows, pattern, *sr, middlewares)
+
+			continue
+		}
+
+		if !p.detailed() {
+			rows = append(rows, printRow{{prefix: "", text: pattern, color: ""}})
+
+			continue
+		}
+
+		for _, meth := range rt.Handlers.Methods(p.MethodOrder) {
+			dh := rt.Handlers[meth]
+			chain := append(middlewares[:len(middlewares):len(middlewares)], dh.Middlewares...)
+			rows = append(rows, p.handler(printCell{prefix: "", text: pattern, color: ""}, meth, dh, chain))
+		}
+	}
+
+	return rows
+}
+
+// tree appends the rows of the routes of the router, below the prefix of its branch.
+func (p RoutePrinter) tree(rows []printRow, prefix string, dr DocRouter) []printRow {
+	patterns := dr.Routes.Patterns()
+
+	for i, pat := range patterns {
+		rt := dr.Routes[pat]
+
+		branch, indent := "├── ", "│   "
+		if i == len(patterns)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		first := printCell{prefix: prefix + branch, text: pat, color: ""}
+		methods := rt.Handlers.Methods(p.MethodOrder)
+		sr := rt.subRouter()
+
+		switch {
+		case sr != nil || len(methods) == 0:
+			row := printRow{first}
+			if sr != nil {
+				row[4] = p.middlewares(sr.middlewares())
+			}
+			rows = append(rows, row)
+
+		case !p.detailed():
+			rows = append(rows, printRow{first})
+
+		default:
+			for j, meth := range methods {
+				cell := first
+				if j > 0 {
+					cell = printCell{prefix: prefix + indent, text: "", color: ""}
+				}
+				rows = append(rows, p.handler(cell, meth, rt.Handlers[meth], rt.Handlers[meth].Middlewares))
+			}
+		}
+
+		if sr != nil {
+			rows = p.tree(rows, prefix+indent, *sr)
+		}
+	}
+
+	return rows
+}
+
+// handler returns the row of a handler.
+func (p RoutePrinter) handler(pattern printCell, method string, dh DocHandler, middlewares []DocMiddleware) printRow {
+	row := printRow{pattern}
+
+	if p.Methods {
+		row[1] = printCell{prefix: "", text: method, color: methodColor(method)}
+	}
+
+	if p.Handlers {
+		row[2] = printCell{prefix: "", text: shortFuncName(funcName(dh.FuncInfo)), color: ""}
+		if dh.File != "" {
+			row[3] = printCell{prefix: "", text: fmt.Sprintf("%s:%d", dh.File, dh.Line), color: ansiDim}
+		}
+	}
+
+	row[4] = p.middlewares(middlewares)
+
+	return row
+}
+
+func (p RoutePrinter) middlewares(middlewares []DocMiddleware) printCell {
+	if !p.Middlewares || len(middlewares) == 0 {
+		return printCell{prefix: "", text: "", color: ""}
+	}
+
+	names := make([]string, len(middlewares))
+	for i, mw := range middlewares {
+		names[i] = shortFuncName(funcName(mw.FuncInfo))
+	}
+
+	return printCell{prefix: "", text: strings.Join(names, " → "), color: ansiDim}
+}
+
+// format joins the cells of the rows, aligned in columns with Align.
+func (p RoutePrinter) format(rows []printRow) []byte {
+	var widths [len(printRow{})]int
+	for _, row := range rows {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c.prefix + c.text); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	for _, row := range rows {
+		last := 0
+		for i, c := range row {
+			if c.prefix+c.text != "" {
+				last = i
+			}
+		}
+
+		for i, c := range row[:last+1] {
+			if widths[i] == 0 || (!p.Align && c.prefix+c.text == "") {
+				continue // column not printed
+			}
+
+			if i > 0 {
+				buf.WriteString("  ")
+			}
+			buf.WriteString(p.color(c.prefix, ansiDim))
+			buf.WriteString(p.color(c.text, c.color))
+
+			if p.Align && i < last {
+				buf.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.prefix+c.text)))
+			}
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+func (p RoutePrinter) color(text, code string) string {
+	if !p.Color || code == "" || text == "" {
+		return text
+	}
+
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+func methodColor(method string) string {
+	switch method {
+	case "GET", "HEAD":
+		return ansiGreen
+	case "POST":
+		return ansiYellow
+	case "PUT", "PATCH":
+		return ansiBlue
+	case "DELETE":
+		return ansiRed
+	default:
+		return ansiMagenta
+	}
+}
@@ -0,0 +1,141 @@
+package docgen_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+func printerDoc() docgen.Doc {
+	doc := oldDoc()
+	articles := doc.Router.Routes["/articles/*"].Router
+	get := handler("GET", "GetArticle")
+	get.File, get.Line = "example.com/api/articles.go", 42
+	articles.Routes["/{id}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{
+		"DELETE": handler("DELETE", "DeleteArticle", "Auth"),
+		"GET":    get,
+	}}
+
+	return doc
+}
+
+func TestRoutePrinter(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		printer docgen.RoutePrinter
+		want    string
+	}{{
+		name:    "flat",
+		printer: docgen.RoutePrinter{},
+		want: `/articles/*/
+/articles/*/{id}
+/ping
+`,
+	}, {
+		name:    "flat methods",
+		printer: docgen.RoutePrinter{Methods: true},
+		want: `/articles/*/  GET
+/articles/*/  POST
+/articles/*/{id}  GET
+/articles/*/{id}  DELETE
+/ping  GET
+`,
+	}, {
+		name:    "flat aligned",
+		printer: docgen.RoutePrinter{Methods: true, Handlers: true, Middlewares: true, Align: true},
+		want: `/articles/*/      GET     api.ListArticles                                   api.Log
+/articles/*/      POST    api.CreateArticle                                  api.Log
+/articles/*/{id}  GET     api.GetArticle     example.com/api/articles.go:42  api.Log
+/articles/*/{id}  DELETE  api.DeleteArticle                                  api.Log → api.Auth
+/ping             GET     api.Ping
+`,
+	}, {
+		name:    "tree",
+		printer: docgen.RoutePrinter{Tree: true},
+		want: `/
+├── /articles/*
+│   ├── /
+│   └── /{id}
+└── /ping
+`,
+	}, {
+		name:    "tree aligned",
+		printer: docgen.RoutePrinter{Tree: true, Methods: true, Middlewares: true, Align: true, MethodOrder: []string{"DELETE"}},
+		want: `/
+├── /articles/*          api.Log
+│   ├── /        GET
+│   │            POST
+│   └── /{id}    DELETE  api.Auth
+│                GET
+└── /ping        GET
+`,
+	}, {
+		name:    "color",
+		printer: docgen.RoutePrinter{Tree: true, Methods: true, Color: true},
+		want: "/\n" +
+			"\x1b[2m├── \x1b[0m/articles/*\n" +
+			"\x1b[2m│   ├── \x1b[0m/  \x1b[32mGET\x1b[0m\n" +
+			"\x1b[2m│   │   \x1b[0m  \x1b[33mPOST\x1b[0m\n" +
+			"\x1b[2m│   └── \x1b[0m/{id}  \x1b[32mGET\x1b[0m\n" +
+			"\x1b[2m│       \x1b[0m  \x1b[31mDELETE\x1b[0m\n" +
+			"\x1b[2m└── \x1b[0m/ping  \x1b[32mGET\x1b[0m\n",
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			c.printer.Writer = &buf
+			if err := c.printer.PrintDoc(printerDoc()); err != nil {
+				t.Fatalf("PrintDoc() error = %v", err)
+			}
+
+			if got := buf.String(); got != c.want {
+				t.Errorf("PrintDoc() =\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRoutePrinter_Print(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := (docgen.RoutePrinter{Writer: &buf, Tree: true}).Print(setupRouter()); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "├── /hubs/*\n│   └── /{hubID}/*\n") {
+		t.Errorf("Print() should draw the sub-routers, got:\n%s", buf.String())
+	}
+
+	if err := (docgen.RoutePrinter{}).Print(nil); err == nil {
+		t.Error("Print(nil) should fail")
+	}
+
+	// the plain listing keeps the order of chi, without building the Doc
+	r := chi.NewRouter()
+	r.Get("/ping", hubIndexHandler)
+	r.Route("/hubs", func(r chi.Router) {
+		r.Get("/{hubID}", hubIndexHandler)
+	})
+	r.Get("/*", hubIndexHandler)
+
+	buf.Reset()
+	if err := (docgen.RoutePrinter{Writer: &buf}).Print(r); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	if want := "/hubs/*/{hubID}\n/ping\n/*\n"; buf.String() != want {
+		t.Errorf("Print() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
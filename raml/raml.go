@@ -13,12 +13,15 @@ const header = `#%RAML 1.0
 `
 
 type RAML struct {
-	Title         string          `yaml:"title,omitempty"`
-	BaseURI       string          `yaml:"baseUri,omitempty"`
-	Protocols     []string        `yaml:"protocols,omitempty"`
-	MediaType     string          `yaml:"mediaType,omitempty"`
-	Version       string          `yaml:"version,omitempty"`
-	Documentation []Documentation `yaml:"documentation,omitempty"`
+	Title           string          `yaml:"title,omitempty"`
+	BaseURI         string          `yaml:"baseUri,omitempty"`
+	Protocols       []string        `yaml:"protocols,omitempty"`
+	MediaType       string          `yaml:"mediaType,omitempty"`
+	Version         string          `yaml:"version,omitempty"`
+	Documentation   []Documentation `yaml:"documentation,omitempty"`
+	Traits          Traits          `yaml:"traits,omitempty"`
+	Types           Types           `yaml:"types,omitempty"`
+	SecuritySchemes SecuritySchemes `yaml:"securitySchemes,omitempty"`
 
 	Resources `yaml:",inline"`
 }
@@ -34,6 +37,36 @@ type Documentation struct {
 	Content string `yaml:"content"`
 }
 
+// Traits declares the reusable traits referenced by the "is" property of the resources.
+type Traits map[string]Trait
+
+// Trait describes a behavior shared by several resources, such as a middleware.
+type Trait struct {
+	Description string `yaml:"description,omitempty"`
+}
+
+// SecuritySchemes declares the security schemes referenced by the "securedBy" property of the resources.
+type SecuritySchemes map[string]SecurityScheme
+
+// SecurityScheme is a RAML security scheme, its Type is "OAuth 2.0", "Basic Authentication",
+// "Digest Authentication", "Pass Through" or a custom "x-" type.
+type SecurityScheme struct {
+	Type        string         `yaml:"type"`
+	Description string         `yaml:"description,omitempty"`
+	Settings    map[string]any `yaml:"settings,omitempty"`
+}
+
+// Types declares the data types referenced by the bodies.
+type Types map[string]*Type
+
+// Type is a RAML data type, the optional properties have a name ending with "?".
+type Type struct {
+	Type       string           `yaml:"type,omitempty"`
+	Pattern    string           `yaml:"pattern,omitempty"`
+	Properties map[string]*Type `yaml:"properties,omitempty"`
+	Items      *Type            `yaml:"items,omitempty"`
+}
+
 type Resources map[string]*Resource
 
 type Resource struct {
@@ -46,6 +79,7 @@ type Resource struct {
 	SecuredBy       []string  `yaml:"securedBy,omitempty"`
 	URIParameters   Body      `yaml:"uriParameters,omitempty"`
 	QueryParameters Body      `yaml:"queryParameters,omitempty"`
+	Headers         Body      `yaml:"headers,omitempty"`
 
 	Resources `yaml:",inline"`
 }
@@ -53,16 +87,20 @@ type Resource struct {
 type Responses map[int]Response
 
 type Response struct {
-	Body `yaml:"body,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Body        `yaml:"body,omitempty"`
 }
 
 type Body map[string]Example // Content-Type to Example
 
+// Example is a body or a parameter. Required is written when not nil: RAML parameters
+// are required by default, so an optional parameter needs an explicit false.
 type Example struct {
 	Example     string `yaml:"example,omitempty"`
 	Type        string `yaml:"type,omitempty"`
+	Pattern     string `yaml:"pattern,omitempty"`
 	Description string `yaml:"description,omitempty"`
-	Required    bool   `yaml:"required,omitempty"`
+	Required    *bool  `yaml:"required,omitempty"`
 }
 
 func (r *RAML) Add(method, route string, resource *Resource) error {
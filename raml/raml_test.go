@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/teal-finance/docgen-yes"
 	"github.com/teal-finance/docgen-yes/raml"
+	fixture "github.com/teal-finance/docgen-yes/testdata/articles"
 )
 
 func TestWalkerRAML(t *testing.T) {
@@ -54,6 +56,196 @@ func TestWalkerRAML(t *testing.T) {
 	}
 }
 
+func TestFromRouter(t *testing.T) {
+	r := Router()
+	r.With(paginate).Get("/archive/{month:[0-9]{2}}/{day}", ListArticles)
+
+	ramlDocs, err := raml.FromRouter(r, raml.Options{
+		Title:           "Big Mux",
+		BaseURI:         "https://bigmux.example.com",
+		Protocols:       []string{},
+		MediaType:       "application/json",
+		Version:         "v1.0",
+		Documentation:   []raml.Documentation{},
+		SecuritySchemes: nil,
+		SchemaDir:       "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := ramlDocs.Resources["/articles"].Resources["/search"].Resources["get"]
+	if !strings.HasPrefix(search.Description, "Search Articles.") {
+		t.Errorf("description = %q, want the SearchArticles comment", search.Description)
+	}
+	if len(search.Is) == 0 || search.Is[len(search.Is)-1] != "Recoverer" {
+		t.Errorf("is = %v, want the router middlewares", search.Is)
+	}
+	if q := search.QueryParameters["q"]; q.Required == nil || !*q.Required || q.Description != "Search terms" {
+		t.Errorf("queryParameters = %v, want the @query annotation", search.QueryParameters)
+	}
+	// RAML parameters are required by default
+	if _, after, _ := strings.Cut(ramlDocs.String(), "description: Page number\n"); !strings.HasPrefix(strings.TrimSpace(after), "required: false\n") {
+		t.Errorf("RAML =\n%s\nwant the optional page with required: false", ramlDocs)
+	}
+	if got := search.Responses[200].Body["application/json"].Type; got != "Articles" {
+		t.Errorf("responses = %v, want the @response annotation", search.Responses)
+	}
+	if _, ok := ramlDocs.Traits["Recoverer"]; !ok {
+		t.Errorf("traits = %v, want Recoverer declared", ramlDocs.Traits)
+	}
+
+	month := ramlDocs.Resources["/archive"].Resources["/{month}"]
+	if got := month.URIParameters["month"].Pattern; got != "[0-9]{2}" {
+		t.Errorf("month pattern = %q, want %q", got, "[0-9]{2}")
+	}
+	day := month.Resources["/{day}"]
+	if _, ok := day.URIParameters["day"]; !ok {
+		t.Errorf("uriParameters = %v, want day", day.URIParameters)
+	}
+	if is := day.Resources["get"].Is; is[len(is)-1] != "paginate" {
+		t.Errorf("is = %v, want paginate as last trait", is)
+	}
+
+	if _, err := yaml.Marshal(ramlDocs); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFromDoc(t *testing.T) {
+	doc, err := docgen.BuildDoc(Router())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := docgen.JSONGenerator{}.GenerateDoc(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err = docgen.ParseJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := raml.Options{
+		Title:           "Big Mux",
+		BaseURI:         "https://bigmux.example.com",
+		Protocols:       []string{},
+		MediaType:       "application/json",
+		Version:         "v1.0",
+		Documentation:   []raml.Documentation{},
+		SecuritySchemes: nil,
+		SchemaDir:       "",
+	}
+
+	fromDoc, err := raml.FromDoc(doc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromRouter, err := raml.FromRouter(Router(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fromDoc.String(), fromRouter.String(); got != want {
+		t.Errorf("FromDoc() =\n%s\nwant FromRouter() =\n%s", got, want)
+	}
+}
+
+func TestFromRouter_types(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/articles", fixture.ListArticles)
+
+	ramlDocs, err := raml.FromRouter(r, raml.Options{
+		Title:           "Articles",
+		BaseURI:         "",
+		Protocols:       []string{},
+		MediaType:       "application/json",
+		Version:         "v1.0",
+		Documentation:   []raml.Documentation{},
+		SecuritySchemes: nil,
+		SchemaDir:       "..",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := ramlDocs.Resources["/articles"].Resources["get"]
+	if got := list.Responses[200].Body["application/json"].Type; got != "Article[]" {
+		t.Errorf("response type = %q, want Article[]", got)
+	}
+
+	article := ramlDocs.Types["Article"]
+	if article == nil {
+		t.Fatalf("types = %v, want Article declared", ramlDocs.Types)
+	}
+	cases := map[string]string{
+		"id":         "integer",
+		"created_at": "datetime",
+		"author":     "Author",
+		"tags?":      "string[]",
+		"related?":   "Article[]",
+		"meta?":      "object",
+	}
+	for name, want := range cases {
+		if p := article.Properties[name]; p == nil || p.Type != want {
+			t.Errorf("property %s = %v, want type %s", name, p, want)
+		}
+	}
+	if _, ok := ramlDocs.Types["Author"]; !ok {
+		t.Errorf("types = %v, want Author declared", ramlDocs.Types)
+	}
+}
+
+func TestFromDoc_securitySchemes(t *testing.T) {
+	_, ann := docgen.ParseAnnotations("Secured.\n@auth basic\n@auth apikey\n@auth oauth2 articles:read")
+	doc := docgen.Doc{
+		Router: docgen.DocRouter{
+			Middlewares: []docgen.DocMiddleware{},
+			Routes: docgen.DocRoutes{
+				"/secured": {Handlers: docgen.DocHandlers{"GET": {
+					Middlewares: []docgen.DocMiddleware{},
+					Method:      "GET",
+					Params:      nil,
+					FuncInfo:    docgen.FuncInfo{Pkg: "example.com/api", Func: "Secured", Annotations: ann},
+				}}},
+			},
+		},
+		Schemas: nil,
+	}
+
+	ramlDocs, err := raml.FromDoc(doc, raml.Options{
+		Title:         "Secured",
+		BaseURI:       "",
+		Protocols:     []string{},
+		MediaType:     "application/json",
+		Version:       "v1.0",
+		Documentation: []raml.Documentation{},
+		SecuritySchemes: raml.SecuritySchemes{
+			"oauth2": {Type: "OAuth 2.0", Description: "", Settings: map[string]any{"accessTokenUri": "https://example.com/token"}},
+		},
+		SchemaDir: "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := ramlDocs.Resources["/secured"].Resources["get"]
+	if got := strings.Join(get.SecuredBy, ","); got != "basic,oauth2" {
+		t.Errorf("securedBy = %s, want basic,oauth2 without the undeclared apikey", got)
+	}
+
+	if len(ramlDocs.SecuritySchemes) != 2 || ramlDocs.SecuritySchemes["basic"].Type != "Basic Authentication" {
+		t.Errorf("securitySchemes = %v, want basic and oauth2 declared", ramlDocs.SecuritySchemes)
+	}
+
+	if !strings.Contains(ramlDocs.String(), "securitySchemes:\n  basic:\n    type: Basic Authentication\n") {
+		t.Errorf("RAML =\n%s\nwant the securitySchemes declared", ramlDocs)
+	}
+}
+
 // Copy-pasted from _examples/raml. We can't simply import it, since it's main pkg.
 func Router() chi.Router {
 	r := chi.NewRouter()
@@ -127,6 +319,9 @@ func ArticleCtx(next http.Handler) http.Handler {
 // Search Articles.
 // Searches the Articles data for a matching article.
 // It's just a stub, but you get the idea.
+// @query q string required Search terms
+// @query page int Page number
+// @response 200 application/json Articles
 func SearchArticles(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, articles)
 }
@@ -0,0 +1,422 @@
+package raml
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// Options sets the root properties of a RAML document built by FromRouter.
+type Options struct {
+	Title         string
+	BaseURI       string
+	Protocols     []string
+	MediaType     string
+	Version       string
+	Documentation []Documentation
+
+	// SecuritySchemes declares the schemes of the @auth annotations,
+	// the "basic" and "bearer" schemes are declared when missing.
+	// The @auth annotations of the other undeclared schemes are left out
+	// of the securedBy properties, a valid document declaring all its schemes.
+	SecuritySchemes SecuritySchemes
+
+	// SchemaDir is a directory of the Go module declaring the types of the @body
+	// and @response annotations, FromRouter declares them as Types when not empty.
+	SchemaDir string
+}
+
+// FromRouter walks a chi router and builds the corresponding RAML document.
+// Each handler comment becomes the description of its method,
+// the chi path placeholders become uriParameters (regexp constraints as pattern)
+// and the middlewares of the chain are listed as traits.
+func FromRouter(r chi.Routes, opts Options) (*RAML, error) {
+	var sr *docgen.SchemaResolver
+	if opts.SchemaDir != "" {
+		sr = docgen.NewSchemaResolver(opts.SchemaDir)
+	}
+
+	doc := newRAML(opts)
+	rs := &docgen.Resolver{KeepAST: false, Workers: 0}
+
+	err := chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		infos := make([]docgen.FuncInfo, 0, len(middlewares))
+		for _, mw := range middlewares {
+			infos = append(infos, rs.FuncInfo(mw))
+		}
+
+		return doc.addHandler(sr, method, route, rs.FuncInfo(handler), infos)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sr != nil {
+		for name, schema := range sr.Schemas {
+			doc.Types[name] = dataType(schema)
+		}
+	}
+
+	return doc, nil
+}
+
+// FromDoc converts a docgen.Doc, such as a Doc loaded by docgen.ParseJSON,
+// into a RAML document, as FromRouter does for a running router.
+// The Schemas of the doc, see docgen.ResolveSchemas, become the Types.
+func FromDoc(d docgen.Doc, opts Options) (*RAML, error) {
+	doc := newRAML(opts)
+
+	var addRouter func(parentPattern string, middlewares []docgen.FuncInfo, dr docgen.DocRouter) error
+	addRouter = func(parentPattern string, middlewares []docgen.FuncInfo, dr docgen.DocRouter) error {
+		// the inline middlewares of the mount point of a sub-router run first
+		for _, mw := range dr.InlineMiddlewares {
+			middlewares = append(middlewares[:len(middlewares):len(middlewares)], mw.FuncInfo)
+		}
+		for _, mw := range dr.Middlewares {
+			middlewares = append(middlewares[:len(middlewares):len(middlewares)], mw.FuncInfo)
+		}
+
+		for _, pat := range dr.Routes.Patterns() {
+			rt := dr.Routes[pat]
+			pattern := docgen.JoinPattern(parentPattern, pat)
+
+			if rt.Router != nil {
+				if err := addRouter(pattern, middlewares, *rt.Router); err != nil {
+					return err
+				}
+			}
+
+			for _, method := range rt.Handlers.Methods(nil) {
+				dh := rt.Handlers[method]
+
+				chain := middlewares[:len(middlewares):len(middlewares)]
+				for _, mw := range dh.Middlewares {
+					chain = append(chain, mw.FuncInfo)
+				}
+
+				if err := doc.addHandler(nil, method, pattern, dh.FuncInfo, chain); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if err := addRouter("", nil, d.Router); err != nil {
+		return nil, err
+	}
+
+	for name, schema := range d.Schemas {
+		doc.Types[name] = dataType(schema)
+	}
+
+	return doc, nil
+}
+
+// Generator implements docgen.DocGenerator, e.g. for docgen.HandlerOpts.Formats.
+type Generator struct {
+	Opts Options
+}
+
+// GenerateDoc implements docgen.DocGenerator.
+func (g Generator) GenerateDoc(doc docgen.Doc) ([]byte, error) {
+	r, err := FromDoc(doc, g.Opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(r.String()), nil
+}
+
+func newRAML(opts Options) *RAML {
+	r := &RAML{
+		Title:           opts.Title,
+		BaseURI:         opts.BaseURI,
+		Protocols:       opts.Protocols,
+		MediaType:       opts.MediaType,
+		Version:         opts.Version,
+		Documentation:   opts.Documentation,
+		Traits:          Traits{},
+		Types:           Types{},
+		SecuritySchemes: SecuritySchemes{},
+		Resources:       Resources{},
+	}
+
+	for name, scheme := range opts.SecuritySchemes {
+		r.SecuritySchemes[name] = scheme
+	}
+
+	return r
+}
+
+// addHandler adds the resource of a handler, resolving the types
+// of its annotations when sr is not nil.
+func (r *RAML) addHandler(sr *docgen.SchemaResolver, method, route string, handlerInfo docgen.FuncInfo, middlewares []docgen.FuncInfo) error {
+	resource := &Resource{
+		DisplayName:     "",
+		Description:     strings.TrimSpace(handlerInfo.Comment),
+		Responses:       Responses{},
+		Body:            Body{},
+		Is:              r.addTraits(middlewares),
+		Example:         "",
+		SecuredBy:       []string{},
+		URIParameters:   Body{},
+		QueryParameters: Body{},
+		Headers:         Body{},
+		Resources:       Resources{},
+	}
+
+	route, params := uriTemplate(route)
+	if ann := handlerInfo.Annotations; ann != nil {
+		if sr != nil {
+			if err := resolveSchemas(sr, handlerInfo, ann); err != nil {
+				return err
+			}
+		}
+		resource.annotate(ann, params)
+		resource.SecuredBy = r.securedBy(ann.Auth)
+	}
+
+	if err := r.Add(method, route, resource); err != nil {
+		return err
+	}
+
+	r.Resources.setURIParameters(route, params)
+
+	return nil
+}
+
+// resolveSchemas sets the Schema of the body and response annotations.
+func resolveSchemas(sr *docgen.SchemaResolver, fi docgen.FuncInfo, ann *docgen.Annotations) error {
+	var err error
+
+	if b := ann.Body; b != nil {
+		if b.Schema, err = sr.Resolve(fi.Package(), b.Type); err != nil {
+			return err
+		}
+	}
+
+	for i, resp := range ann.Responses {
+		if resp.Type == "" {
+			continue
+		}
+		if ann.Responses[i].Schema, err = sr.Resolve(fi.Package(), resp.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dataType converts a JSON Schema into a RAML data type.
+func dataType(s *docgen.Schema) *Type {
+	t := &Type{
+		Type:       typeExpression(s),
+		Pattern:    s.Pattern,
+		Properties: nil,
+		Items:      nil,
+	}
+
+	switch {
+	case s.Type == "object" && s.Properties != nil:
+		required := map[string]bool{}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+
+		t.Properties = make(map[string]*Type, len(s.Properties))
+		for name, p := range s.Properties {
+			if !required[name] {
+				name += "?"
+			}
+			t.Properties[name] = dataType(p)
+		}
+
+	case s.AdditionalProperties != nil:
+		t.Properties = map[string]*Type{"//": dataType(s.AdditionalProperties)}
+
+	case s.Type == "array" && s.Items != nil && typeExpression(s.Items) == "object":
+		t.Type = "array"
+		t.Items = dataType(s.Items)
+	}
+
+	return t
+}
+
+// typeExpression returns the RAML type expression of a schema,
+// e.g. "Article" for a $ref, "string[]" for an array of strings.
+func typeExpression(s *docgen.Schema) string {
+	switch {
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, docgen.SchemaRefPrefix)
+	case s.Type == "array" && s.Items != nil:
+		if items := typeExpression(s.Items); items != "object" {
+			return items + "[]"
+		}
+
+		return "array"
+	case s.Format == "date-time":
+		return "datetime"
+	case s.Type == "":
+		return "any"
+	default:
+		return s.Type
+	}
+}
+
+// addTraits declares a trait for each resolvable middleware
+// and returns the trait names in execution order.
+func (r *RAML) addTraits(middlewares []docgen.FuncInfo) []string {
+	names := make([]string, 0, len(middlewares))
+
+	for _, info := range middlewares {
+		if info.Func == "" {
+			continue
+		}
+
+		if _, found := r.Traits[info.Func]; !found {
+			r.Traits[info.Func] = Trait{Description: strings.TrimSpace(info.Comment)}
+		}
+		names = append(names, info.Func)
+	}
+
+	return names
+}
+
+// annotate fills the resource from the annotations of the handler comment,
+// the annotated path parameters are documented in params.
+func (r *Resource) annotate(ann *docgen.Annotations, params map[string]Example) {
+	for _, p := range ann.Params {
+		if param, ok := params[p.Name]; ok {
+			param.Type = p.JSONType()
+			param.Description = p.Description
+			params[p.Name] = param
+		}
+	}
+
+	for _, p := range ann.Query {
+		r.QueryParameters[p.Name] = parameter(p)
+	}
+
+	for _, p := range ann.Headers {
+		r.Headers[p.Name] = parameter(p)
+	}
+
+	if b := ann.Body; b != nil {
+		r.Body[b.ContentType] = Example{Example: "", Type: bodyType(b.Type, b.Schema), Pattern: "", Description: b.Description, Required: required(true)}
+	}
+
+	for _, resp := range ann.Responses {
+		response := Response{Description: resp.Description, Body: Body{}}
+		if resp.ContentType != "" {
+			response.Body[resp.ContentType] = Example{Example: "", Type: bodyType(resp.Type, resp.Schema), Pattern: "", Description: "", Required: nil}
+		}
+		r.Responses[resp.Status] = response
+	}
+
+	if ann.Deprecated != nil {
+		r.Description = strings.TrimSpace(r.Description + "\n\nDeprecated: " + ann.Deprecated.Reason)
+	}
+}
+
+// securedBy returns the declared schemes of the @auth annotations,
+// declaring the "basic" and "bearer" schemes when missing.
+func (r *RAML) securedBy(auth []docgen.AuthAnnotation) []string {
+	names := make([]string, 0, len(auth))
+
+	for _, a := range auth {
+		if _, ok := r.SecuritySchemes[a.Scheme]; !ok {
+			switch a.Scheme {
+			case "basic":
+				r.SecuritySchemes[a.Scheme] = SecurityScheme{Type: "Basic Authentication", Description: "", Settings: nil}
+			case "bearer":
+				r.SecuritySchemes[a.Scheme] = SecurityScheme{
+					Type:        "x-bearer",
+					Description: "Bearer token in the Authorization header.",
+					Settings:    nil,
+				}
+			default:
+				continue // undeclared scheme
+			}
+		}
+
+		names = append(names, a.Scheme)
+	}
+
+	return names
+}
+
+// bodyType is the name of the declared type when resolved, else the Go type.
+func bodyType(typ string, s *docgen.Schema) string {
+	if s == nil {
+		return typ
+	}
+
+	return typeExpression(s)
+}
+
+func parameter(p docgen.ParamAnnotation) Example {
+	return Example{
+		Example:     "",
+		Type:        p.JSONType(),
+		Pattern:     "",
+		Description: p.Description,
+		Required:    required(p.Required),
+	}
+}
+
+func required(b bool) *bool {
+	return &b
+}
+
+// setURIParameters attaches each path parameter to the resource node
+// of the route segment declaring it.
+func (r Resources) setURIParameters(route string, params map[string]Example) {
+	currentNode := r
+
+	for _, part := range strings.Split(route, "/") {
+		if part == "" {
+			continue
+		}
+
+		node, found := currentNode["/"+part]
+		if !found {
+			return
+		}
+
+		for name, param := range params {
+			if strings.Contains(part, "{"+name+"}") {
+				node.URIParameters[name] = param
+			}
+		}
+		currentNode = node.Resources
+	}
+}
+
+// uriTemplate converts a chi route into a RAML URI template and extracts its parameters:
+// "/{id}" and "/{id:[0-9]+}" become "/{id}" and a trailing "*" becomes "{wildcard}".
+func uriTemplate(route string) (string, map[string]Example) {
+	params := map[string]Example{}
+
+	for _, p := range docgen.ParsePattern(route) {
+		name, description := p.Name, ""
+		if p.CatchAll {
+			name, description = "wildcard", "Catch-all remainder of the path"
+		}
+
+		route = strings.Replace(route, p.Placeholder(), "{"+name+"}", 1)
+		params[name] = Example{
+			Example:     "",
+			Type:        "string",
+			Pattern:     p.Regex,
+			Description: description,
+			Required:    required(true),
+		}
+	}
+
+	return route, params
+}
@@ -0,0 +1,254 @@
+package docgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Resolver resolves the FuncInfo of the handlers and middlewares of a build:
+// each source file is parsed once, each function is resolved once,
+// and BuildDoc resolves the functions concurrently.
+// The zero value is ready to use, and a Resolver is safe for concurrent use.
+// The FuncInfos of a function share their Annotations.
+type Resolver struct {
+	// KeepAST sets the FuncInfo.ASTFile of the resolved functions,
+	// shared by the functions of the same file and never serialized.
+	KeepAST bool
+
+	// Workers is the maximum number of functions resolved concurrently,
+	// runtime.GOMAXPROCS(0) when zero.
+	Workers int
+
+	files sync.Map // file name : *sourceFile
+	funcs sync.Map // funcKey : FuncInfo
+}
+
+// funcKey identifies a function resolved by a Resolver: the comment depends on its type.
+type funcKey struct {
+	entry uintptr
+	typ   reflect.Type
+}
+
+// sourceFile is a source file parsed once: its package clause,
+// then the whole file when a comment is needed.
+type sourceFile struct {
+	name string
+
+	pkgOnce sync.Once
+	pkg     string
+
+	astOnce sync.Once
+	fset    *token.FileSet
+	file    *ast.File // nil when the file cannot be parsed
+}
+
+// BuildDoc builds the Doc of the router r, as the BuildDoc function.
+func (rs *Resolver) BuildDoc(r chi.Routes) (Doc, error) {
+	d := Doc{}
+
+	d.Router = rs.BuildDocRouter(r)
+
+	return d, nil
+}
+
+// BuildDocRouter builds the DocRouter of the router r: a first walk collects
+// the handlers and middlewares, resolved concurrently, then the second walk
+// gets their FuncInfo from the cache.
+func (rs *Resolver) BuildDocRouter(r chi.Routes) DocRouter {
+	var funcs []any
+	buildDocRouter("", r, func(i any) FuncInfo {
+		funcs = append(funcs, i)
+
+		return FuncInfo{}
+	})
+
+	rs.resolve(funcs)
+
+	return buildDocRouter("", r, rs.FuncInfo)
+}
+
+// resolve resolves the functions with a pool of Workers goroutines.
+func (rs *Resolver) resolve(funcs []any) {
+	workers := rs.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	jobs := make(chan any)
+
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rs.FuncInfo(i)
+			}
+		}()
+	}
+
+	seen := make(map[funcKey]bool, len(funcs))
+	for _, i := range funcs {
+		frame := getCallerFrame(i)
+		if frame == nil {
+			continue // unresolvable without parsing
+		}
+
+		key := funcKey{entry: frame.Entry, typ: reflect.TypeOf(i)}
+		if !seen[key] {
+			seen[key] = true
+			jobs <- i
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+}
+
+// FuncInfo returns the FuncInfo of a function or of a pointer to an http.Handler,
+// resolved on the first call.
+func (rs *Resolver) FuncInfo(i any) FuncInfo {
+	frame := getCallerFrame(i)
+	if frame == nil {
+		return FuncInfo{
+			Pkg:          "",
+			Func:         "",
+			Comment:      "",
+			File:         "",
+			Line:         0,
+			Anonymous:    false,
+			Unresolvable: true,
+		}
+	}
+
+	key := funcKey{entry: frame.Entry, typ: reflect.TypeOf(i)}
+	if fi, ok := rs.funcs.Load(key); ok {
+		return fi.(FuncInfo)
+	}
+
+	fi, _ := rs.funcs.LoadOrStore(key, rs.funcInfo(i, frame))
+
+	return fi.(FuncInfo)
+}
+
+func (rs *Resolver) funcInfo(i any, frame *runtime.Frame) FuncInfo {
+	fi := FuncInfo{
+		Pkg:          "",
+		Func:         "",
+		Comment:      "",
+		File:         "",
+		Line:         0,
+		Anonymous:    false,
+		Unresolvable: false,
+	}
+
+	sf := rs.source(frame.File)
+
+	pkgName := sf.pkgName()
+	if pkgName == "chi" {
+		fi.Unresolvable = true
+	}
+	funcPath := frame.Func.Name()
+
+	idx := strings.Index(funcPath, "/"+pkgName)
+	if idx > 0 {
+		fi.Pkg = funcPath[:idx+1+len(pkgName)]
+		fi.Func = funcPath[idx+2+len(pkgName):]
+	} else {
+		fi.Func = funcPath
+	}
+
+	if strings.Index(fi.Func, ".func") > 0 {
+		fi.Anonymous = true
+	}
+
+	fi.File = sourcePath(frame.File)
+	fi.Line = frame.Line
+
+	// Check if file info is unresolvable
+	if !strings.Contains(funcPath, pkgName) {
+		fi.Unresolvable = true
+	}
+
+	if !fi.Unresolvable {
+		fi.Comment = sf.funcComment(i, frame.Line)
+		fi.Comment, fi.Annotations = ParseAnnotations(fi.Comment)
+		if rs.KeepAST {
+			fi.ASTFile = sf.ast()
+		}
+	}
+
+	return fi
+}
+
+// source returns the source file of the name.
+func (rs *Resolver) source(name string) *sourceFile {
+	v, _ := rs.files.LoadOrStore(name, &sourceFile{
+		name:    name,
+		pkgOnce: sync.Once{},
+		pkg:     "",
+		astOnce: sync.Once{},
+		fset:    nil,
+		file:    nil,
+	})
+
+	return v.(*sourceFile)
+}
+
+func (sf *sourceFile) pkgName() string {
+	sf.pkgOnce.Do(func() {
+		file, err := parser.ParseFile(token.NewFileSet(), sf.name, nil, parser.PackageClauseOnly)
+		if err == nil && file.Name != nil {
+			sf.pkg = file.Name.Name
+		}
+	})
+
+	return sf.pkg
+}
+
+// ast returns the file parsed with its comments, nil on error.
+func (sf *sourceFile) ast() *ast.File {
+	sf.astOnce.Do(func() {
+		sf.fset = token.NewFileSet()
+
+		file, err := parser.ParseFile(sf.fset, sf.name, nil, parser.ParseComments)
+		if err == nil {
+			sf.file = file
+		}
+	})
+
+	return sf.file
+}
+
+// funcComment returns the comment of the function declared at the line,
+// or of the type of i.
+func (sf *sourceFile) funcComment(i any, line int) string {
+	file := sf.ast()
+	if file == nil || len(file.Comments) == 0 {
+		return ""
+	}
+
+	typNames := strings.Split(reflect.TypeOf(i).String(), ".")
+	typName := typNames[len(typNames)-1]
+
+	for _, cmt := range file.Comments {
+		if strings.HasPrefix(cmt.Text(), typName+" ") {
+			return cmt.Text()
+		}
+	}
+
+	for _, cmt := range file.Comments {
+		if sf.fset.Position(cmt.End()).Line+1 == line {
+			return cmt.Text()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,81 @@
+package docgen_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/testdata/articles"
+)
+
+func TestResolver_FuncInfo(t *testing.T) {
+	t.Parallel()
+
+	rs := &docgen.Resolver{KeepAST: false, Workers: 0}
+
+	fi := rs.FuncInfo(articles.ListArticles)
+	if fi.Func != "ListArticles" || fi.Comment != "ListArticles returns all the Articles.\n" || fi.ASTFile != nil {
+		t.Errorf("FuncInfo() = %+v, want ListArticles without ASTFile", fi)
+	}
+	if got := docgen.GetFuncInfo(articles.ListArticles); !reflect.DeepEqual(got, fi) {
+		t.Errorf("GetFuncInfo() = %+v, want %+v", got, fi)
+	}
+	if got := rs.FuncInfo(articles.ListArticles); !reflect.DeepEqual(got, fi) {
+		t.Errorf("FuncInfo() from the cache = %+v, want %+v", got, fi)
+	}
+
+	// the functions of a file share its AST
+	rs = &docgen.Resolver{KeepAST: true, Workers: 0}
+	fi = rs.FuncInfo(articles.ListArticles)
+	if fi.ASTFile == nil || fi.ASTFile != rs.FuncInfo(articles.CreateArticle).ASTFile {
+		t.Errorf("FuncInfo().ASTFile = %p, want the AST of articles.go", fi.ASTFile)
+	}
+}
+
+func TestResolver_BuildDoc(t *testing.T) {
+	t.Parallel()
+
+	want, err := docgen.BuildDoc(setupRouter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON, err := docgen.JSONGenerator{}.GenerateDoc(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rs := range []*docgen.Resolver{
+		{KeepAST: false, Workers: 1},
+		{KeepAST: false, Workers: 8},
+		{KeepAST: true, Workers: 0},
+	} {
+		doc, err := rs.BuildDoc(setupRouter())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !rs.KeepAST && !reflect.DeepEqual(doc, want) {
+			t.Errorf("Resolver{Workers: %d}.BuildDoc() differs from BuildDoc()", rs.Workers)
+		}
+
+		// the AST is never serialized
+		got, err := docgen.JSONGenerator{}.GenerateDoc(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, wantJSON) {
+			t.Errorf("JSON of Resolver{KeepAST: %v, Workers: %d}.BuildDoc() differs from BuildDoc()", rs.KeepAST, rs.Workers)
+		}
+	}
+}
+
+func BenchmarkBuildDoc(b *testing.B) {
+	r := articles.Router()
+
+	for n := 0; n < b.N; n++ {
+		if _, err := docgen.BuildDoc(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
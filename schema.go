@@ -0,0 +1,454 @@
+package docgen
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaRefPrefix is the prefix of the $ref pointing to Doc.Schemas,
+// it is a valid JSON pointer within the JSON of the Doc.
+const SchemaRefPrefix = "#/schemas/"
+
+// Schema is the subset of JSON Schema describing the Go types
+// referenced by the @body and @response annotations.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"                 yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"                 yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty"               yaml:"format,omitempty"`
+	Title                string             `json:"title,omitempty"                yaml:"title,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"              yaml:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"           yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"             yaml:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"                yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+func simpleSchema(typ, format string) *Schema {
+	return &Schema{
+		Ref:                  "",
+		Type:                 typ,
+		Format:               format,
+		Title:                "",
+		Pattern:              "",
+		Properties:           nil,
+		Required:             nil,
+		Items:                nil,
+		AdditionalProperties: nil,
+	}
+}
+
+func arraySchema(items *Schema) *Schema {
+	s := simpleSchema("array", "")
+	s.Items = items
+
+	return s
+}
+
+func refSchema(name string) *Schema {
+	s := simpleSchema("", "")
+	s.Ref = SchemaRefPrefix + name
+
+	return s
+}
+
+// ResolveSchemas converts the Go types referenced by the @body and @response annotations
+// of the handlers into JSON Schema: the annotations get a Schema, usually a $ref
+// to the named types stored in doc.Schemas.
+// The types are looked up in the package of the handler, or in one of its imports
+// when qualified (e.g. "models.Article"). dir is a directory of the Go module.
+// All the types are processed, the returned error lists the unresolved ones.
+func ResolveSchemas(doc *Doc, dir string) error {
+	sr := NewSchemaResolver(dir)
+	failures := []string{}
+
+	resolve := func(fi FuncInfo, typ string) *Schema {
+		schema, err := sr.Resolve(fi.Package(), typ)
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+
+		return schema
+	}
+
+	var walkRouter func(dr DocRouter)
+	walkRouter = func(dr DocRouter) {
+		for _, rt := range dr.Routes {
+			if rt.Router != nil {
+				walkRouter(*rt.Router)
+			}
+
+			for _, dh := range rt.Handlers {
+				ann := dh.Annotations
+				if ann == nil {
+					continue
+				}
+				if ann.Body != nil {
+					ann.Body.Schema = resolve(dh.FuncInfo, ann.Body.Type)
+				}
+				for i, resp := range ann.Responses {
+					if resp.Type != "" {
+						ann.Responses[i].Schema = resolve(dh.FuncInfo, resp.Type)
+					}
+				}
+			}
+		}
+	}
+
+	walkRouter(doc.Router)
+	doc.Schemas = sr.Schemas
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+
+		return errors.New(strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
+
+// SchemaResolver converts Go types into JSON Schema,
+// the named struct types are stored in Schemas and referenced with $ref.
+// The packages are imported from the export data compiled by the go command,
+// as BuildDocFromSource does.
+type SchemaResolver struct {
+	// Dir is a directory of the Go module resolving the import paths.
+	Dir string
+
+	// Schemas holds the definitions of the named types.
+	Schemas map[string]*Schema
+
+	loader     *exportLoader
+	names      map[*types.TypeName]string
+	flattening map[*types.Struct]bool // structs being converted, to stop the self-embedding ones
+}
+
+// NewSchemaResolver creates a SchemaResolver for the module containing dir.
+func NewSchemaResolver(dir string) *SchemaResolver {
+	return &SchemaResolver{
+		Dir:        dir,
+		Schemas:    map[string]*Schema{},
+		loader:     newExportLoader(token.NewFileSet()),
+		names:      map[*types.TypeName]string{},
+		flattening: map[*types.Struct]bool{},
+	}
+}
+
+// Resolve returns the schema of the type expression typ as written in the package pkgPath:
+// "Article", "*Article", "[]Article", "models.Article" or "github.com/me/models.Article".
+func (sr *SchemaResolver) Resolve(pkgPath, typ string) (*Schema, error) {
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		items, err := sr.Resolve(pkgPath, typ[2:])
+		if err != nil {
+			return nil, err
+		}
+
+		return arraySchema(items), nil
+
+	case strings.HasPrefix(typ, "*"):
+		return sr.Resolve(pkgPath, typ[1:])
+	}
+
+	if obj := types.Universe.Lookup(typ); obj != nil {
+		return sr.schema(obj.Type()), nil
+	}
+
+	qualifier, name := "", typ
+	if dot := strings.LastIndexByte(typ, '.'); dot > 0 {
+		qualifier, name = typ[:dot], typ[dot+1:]
+	}
+
+	pkg, err := sr.lookupPackage(pkgPath, qualifier)
+	if err != nil {
+		return nil, fmt.Errorf("docgen: cannot resolve type %q: %w", typ, err)
+	}
+
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("docgen: cannot resolve type %q: %s not declared in %s", typ, name, pkg.Path())
+	}
+
+	return sr.schema(obj.Type()), nil
+}
+
+// lookupPackage returns the package pkgPath, or the package it imports as qualifier.
+// The qualifier may also be an import path, e.g. "github.com/me/models".
+func (sr *SchemaResolver) lookupPackage(pkgPath, qualifier string) (*types.Package, error) {
+	if strings.Contains(qualifier, "/") {
+		return sr.loader.importPackage(qualifier, sr.Dir)
+	}
+
+	if pkgPath == "" {
+		return nil, errors.New("unknown package")
+	}
+
+	pkg, err := sr.loader.importPackage(pkgPath, sr.Dir)
+	if err != nil || qualifier == "" {
+		return pkg, err
+	}
+
+	if imp := sr.loader.importedPath(pkgPath, qualifier); imp != "" {
+		return sr.loader.importPackage(imp, sr.Dir)
+	}
+
+	return nil, fmt.Errorf("%s not imported by %s", qualifier, pkgPath)
+}
+
+func (sr *SchemaResolver) schema(t types.Type) *Schema {
+	switch t := t.(type) {
+	case *types.Named:
+		return sr.named(t)
+
+	case *types.Pointer:
+		return sr.schema(t.Elem())
+
+	case *types.Basic:
+		return basicSchema(t)
+
+	case *types.Slice:
+		if b, ok := t.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return simpleSchema("string", "byte")
+		}
+
+		return arraySchema(sr.schema(t.Elem()))
+
+	case *types.Array:
+		return arraySchema(sr.schema(t.Elem()))
+
+	case *types.Map:
+		m := simpleSchema("object", "")
+		m.AdditionalProperties = sr.schema(t.Elem())
+
+		return m
+
+	case *types.Struct:
+		return sr.structSchema(t)
+
+	default: // interfaces, funcs and channels accept anything
+		return simpleSchema("", "")
+	}
+}
+
+// named stores the definition of a named struct and returns its $ref,
+// the other named types are inlined.
+func (sr *SchemaResolver) named(t *types.Named) *Schema {
+	obj := t.Obj()
+
+	if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+		return simpleSchema("string", "date-time")
+	}
+
+	if marshalsItself(t, "MarshalJSON") || marshalsItself(t, "MarshalText") {
+		return simpleSchema("string", "")
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return sr.schema(t.Underlying())
+	}
+
+	if name, ok := sr.names[obj]; ok {
+		return refSchema(name)
+	}
+
+	name := sr.uniqueName(obj)
+
+	// register before converting the fields to support recursive types
+	sr.names[obj] = name
+	sr.Schemas[name] = simpleSchema("object", "")
+	*sr.Schemas[name] = *sr.structSchema(st)
+	sr.Schemas[name].Title = name
+
+	return refSchema(name)
+}
+
+// uniqueName returns the name of the type, qualified by its package name when taken,
+// then numbered until unique: "Article", "models.Article", "models.Article2"...
+func (sr *SchemaResolver) uniqueName(obj *types.TypeName) string {
+	name := obj.Name()
+	if _, taken := sr.Schemas[name]; !taken || obj.Pkg() == nil {
+		return name
+	}
+
+	name = obj.Pkg().Name() + "." + name
+	for i, base := 2, name; ; i++ {
+		if _, taken := sr.Schemas[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+// structSchema converts the exported fields according to their json tag,
+// the fields of the embedded structs without tag are promoted, even unexported
+// as encoding/json does. A struct embedding itself is not promoted again.
+func (sr *SchemaResolver) structSchema(st *types.Struct) *Schema {
+	s := simpleSchema("object", "")
+	s.Properties = map[string]*Schema{}
+
+	byName := map[string][]schemaField{}
+	names := []string{}
+
+	for _, f := range sr.fields(st, 0) {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	for _, name := range names {
+		f, ok := dominantField(byName[name])
+		if !ok {
+			continue
+		}
+
+		s.Properties[name] = f.schema
+		if f.required {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+
+	return s
+}
+
+// schemaField is a JSON field of a struct, possibly promoted from an embedded struct.
+type schemaField struct {
+	name     string
+	depth    int // number of embedded structs to go through
+	tagged   bool
+	required bool
+	schema   *Schema
+}
+
+// fields lists the JSON fields of the struct, followed by the ones of its embedded structs.
+func (sr *SchemaResolver) fields(st *types.Struct, depth int) []schemaField {
+	if sr.flattening[st] {
+		return nil
+	}
+	sr.flattening[st] = true
+	defer delete(sr.flattening, st)
+
+	fields := []schemaField{}
+	promoted := []schemaField{}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+
+		embedded, isEmbedded := embeddedStruct(field)
+		if !field.Exported() && !isEmbedded || name == "-" && opts == "" {
+			continue
+		}
+
+		if isEmbedded && name == "" {
+			promoted = append(promoted, sr.fields(embedded, depth+1)...)
+
+			continue
+		}
+
+		f := schemaField{
+			name:     name,
+			depth:    depth,
+			tagged:   name != "",
+			required: !strings.Contains(opts, "omitempty"),
+			schema:   sr.schema(field.Type()),
+		}
+		if !f.tagged {
+			f.name = field.Name()
+		}
+		if strings.Contains(opts, "string") {
+			f.schema = simpleSchema("string", "")
+		}
+
+		fields = append(fields, f)
+	}
+
+	return append(fields, promoted...)
+}
+
+// dominantField returns the field kept by encoding/json among the fields of the same name:
+// the shallowest one, or the only tagged one at this depth. Other conflicts drop the name.
+func dominantField(fields []schemaField) (schemaField, bool) {
+	depth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < depth {
+			depth = f.depth
+		}
+	}
+
+	var shallowest, tagged []schemaField
+
+	for _, f := range fields {
+		if f.depth == depth {
+			shallowest = append(shallowest, f)
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+	}
+
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	default:
+		return schemaField{}, false
+	}
+}
+
+// embeddedStruct returns the struct of an embedded field,
+// except for the types having their own JSON marshaling.
+func embeddedStruct(field *types.Var) (*types.Struct, bool) {
+	if !field.Embedded() {
+		return nil, false
+	}
+
+	t := field.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	if named, ok := t.(*types.Named); ok && (marshalsItself(named, "MarshalJSON") || marshalsItself(named, "MarshalText")) {
+		return nil, false
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+
+	return st, ok
+}
+
+func basicSchema(t *types.Basic) *Schema {
+	info := t.Info()
+
+	switch {
+	case info&types.IsBoolean != 0:
+		return simpleSchema("boolean", "")
+	case info&types.IsInteger != 0:
+		return simpleSchema("integer", "")
+	case info&types.IsFloat != 0:
+		return simpleSchema("number", "")
+	case info&types.IsString != 0:
+		return simpleSchema("string", "")
+	default:
+		return simpleSchema("", "")
+	}
+}
+
+// marshalsItself reports whether t or *t has the method name,
+// such as MarshalJSON or MarshalText, making its JSON a string in most cases.
+func marshalsItself(t *types.Named, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, t.Obj().Pkg(), name)
+	_, ok := obj.(*types.Func)
+
+	return ok
+}
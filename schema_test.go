@@ -0,0 +1,240 @@
+package docgen_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/testdata/articles"
+)
+
+const articlesPkg = "github.com/teal-finance/docgen-yes/testdata/articles"
+
+func TestSchemaResolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	// the resolver caches the type-checked packages: the cases share it and run sequentially
+	sr := docgen.NewSchemaResolver(".")
+
+	cases := []struct {
+		name    string
+		pkgPath string
+		typ     string
+		want    string
+		wantErr bool
+	}{{
+		name:    "struct",
+		pkgPath: articlesPkg,
+		typ:     "Article",
+		want:    `{"$ref":"#/schemas/Article"}`,
+	}, {
+		name:    "slice of pointers",
+		pkgPath: articlesPkg,
+		typ:     "[]*Article",
+		want:    `{"type":"array","items":{"$ref":"#/schemas/Article"}}`,
+	}, {
+		name:    "predeclared",
+		pkgPath: "",
+		typ:     "string",
+		want:    `{"type":"string"}`,
+	}, {
+		name:    "imported package",
+		pkgPath: articlesPkg,
+		typ:     "time.Time",
+		want:    `{"type":"string","format":"date-time"}`,
+	}, {
+		name:    "import path",
+		pkgPath: "",
+		typ:     articlesPkg + ".Author",
+		want:    `{"$ref":"#/schemas/Author"}`,
+	}, {
+		name:    "undeclared",
+		pkgPath: articlesPkg,
+		typ:     "Missing",
+		wantErr: true,
+	}, {
+		name:    "unknown package",
+		pkgPath: "",
+		typ:     "Article",
+		wantErr: true,
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			s, err := sr.Resolve(c.pkgPath, c.typ)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if c.wantErr {
+				return
+			}
+
+			if got, _ := json.Marshal(s); string(got) != c.want {
+				t.Errorf("Resolve() = %s, want %s", got, c.want)
+			}
+		})
+	}
+
+	article, _ := json.Marshal(sr.Schemas["Article"])
+	want := `{"type":"object","title":"Article","properties":{` +
+		`"author":{"$ref":"#/schemas/Author"},` +
+		`"created_at":{"type":"string","format":"date-time"},` +
+		`"id":{"type":"integer"},` +
+		`"meta":{"type":"object","additionalProperties":{"type":"string"}},` +
+		`"related":{"type":"array","items":{"$ref":"#/schemas/Article"}},` +
+		`"score":{"type":"string"},` +
+		`"tags":{"type":"array","items":{"type":"string"}},` +
+		`"title":{"type":"string"}},` +
+		`"required":["author","created_at","id","score","title"]}`
+	if string(article) != want {
+		t.Errorf("Article = %s\nwant %s", article, want)
+	}
+
+	if _, ok := sr.Schemas["Audit"]; ok {
+		t.Error("the embedded Audit should be promoted into Article, not declared")
+	}
+}
+
+func TestSchemaResolver_Resolve_embedded(t *testing.T) {
+	t.Parallel()
+
+	sr := docgen.NewSchemaResolver(".")
+
+	cases := []struct {
+		typ  string
+		want string
+	}{{
+		typ: "Comment", // unexported embedded struct
+		want: `{"type":"object","title":"Comment","properties":{` +
+			`"id":{"type":"integer"},"text":{"type":"string"}},"required":["id","text"]}`,
+	}, {
+		typ: "Reply", // outer field shadowing a promoted one
+		want: `{"type":"object","title":"Reply","properties":{` +
+			`"id":{"type":"string"},"text":{"type":"string"}},"required":["id","text"]}`,
+	}, {
+		typ: "Draft", // conflicting promoted fields
+		want: `{"type":"object","title":"Draft","properties":{` +
+			`"id":{"type":"integer"}},"required":["id"]}`,
+	}, {
+		typ: "Node", // self-embedding struct
+		want: `{"type":"object","title":"Node","properties":{` +
+			`"name":{"type":"string"}},"required":["name"]}`,
+	}}
+
+	for _, c := range cases {
+		if _, err := sr.Resolve(articlesPkg, c.typ); err != nil {
+			t.Fatalf("Resolve(%s) error = %v", c.typ, err)
+		}
+
+		if got, _ := json.Marshal(sr.Schemas[c.typ]); string(got) != c.want {
+			t.Errorf("%s = %s\nwant %s", c.typ, got, c.want)
+		}
+	}
+}
+
+func TestSchemaResolver_Resolve_names(t *testing.T) {
+	t.Parallel()
+
+	sr := docgen.NewSchemaResolver(".")
+
+	cases := []struct {
+		pkgPath string
+		want    string
+	}{
+		{pkgPath: articlesPkg, want: "Article"},
+		{pkgPath: "github.com/teal-finance/docgen-yes/testdata/models/a", want: "models.Article"},
+		{pkgPath: "github.com/teal-finance/docgen-yes/testdata/models/b", want: "models.Article2"},
+		{pkgPath: "github.com/teal-finance/docgen-yes/testdata/models/a", want: "models.Article"},
+	}
+
+	for _, c := range cases {
+		s, err := sr.Resolve(c.pkgPath, "Article")
+		if err != nil {
+			t.Fatalf("Resolve(%s.Article) error = %v", c.pkgPath, err)
+		}
+
+		if s.Ref != docgen.SchemaRefPrefix+c.want {
+			t.Errorf("Resolve(%s.Article) = %s, want the $ref of %s", c.pkgPath, s.Ref, c.want)
+		}
+	}
+
+	if len(sr.Schemas) != 4 { // with the Author of the articles
+		t.Errorf("Schemas = %d, want 4", len(sr.Schemas))
+	}
+}
+
+func TestResolveSchemas(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Get("/articles", articles.ListArticles)
+	r.Post("/articles", articles.CreateArticle)
+
+	doc, err := docgen.BuildDoc(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = docgen.ResolveSchemas(&doc, ".")
+	if err == nil || !strings.Contains(err.Error(), `"Missing"`) {
+		t.Errorf("ResolveSchemas() error = %v, want the unresolved Missing type", err)
+	}
+
+	post := doc.Router.Routes["/articles"].Handlers["POST"].Annotations
+	if post.Body.Schema == nil || post.Body.Schema.Ref != "#/schemas/ArticleRequest" {
+		t.Errorf("body schema = %+v, want ArticleRequest", post.Body.Schema)
+	}
+	if post.Responses[1].Schema != nil {
+		t.Errorf("unresolved response schema = %+v, want nil", post.Responses[1].Schema)
+	}
+
+	for _, name := range []string{"Article", "ArticleRequest", "Author"} {
+		if _, ok := doc.Schemas[name]; !ok {
+			t.Errorf("schemas = %v, want %s", doc.Schemas, name)
+		}
+	}
+
+	md := docgen.MarkdownRoutesDoc(r, docgen.MarkdownOpts{
+		ProjectPath:        "",
+		Intro:              "",
+		ForceRelativeLinks: false,
+		URLMap:             nil,
+		SchemaDir:          ".",
+		MethodOrder:        nil,
+	})
+	if !strings.HasPrefix(md, "ERROR: ") {
+		t.Errorf("markdown should report the unresolved type, got %s", md)
+	}
+}
+
+func TestMarkdownRoutesDoc_schemas(t *testing.T) {
+	t.Parallel()
+
+	r := chi.NewRouter()
+	r.Get("/articles", articles.ListArticles)
+
+	md := docgen.MarkdownRoutesDoc(r, docgen.MarkdownOpts{
+		ProjectPath:        "",
+		Intro:              "",
+		ForceRelativeLinks: false,
+		URLMap:             nil,
+		SchemaDir:          ".",
+		MethodOrder:        nil,
+	})
+
+	for _, want := range []string{
+		"- Response **200** `application/json` [`[]Article`](#article)",
+		"## Schemas",
+		"### Article\n\n```json\n{\n  \"type\": \"object\"",
+		"### Author",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown should contain %q, got:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package docgen
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// modulePaths caches the module path declared by the go.mod of a directory
+// ("" when there is none), it is shared by all the GetFuncInfo calls.
+var modulePaths sync.Map // directory : module path
+
+// sourcePath converts the absolute file name of a runtime frame
+// into a path starting with the import path of its package,
+// e.g. "github.com/go-chi/chi/v5/mux.go", so the generators can link to it.
+// The file is returned unchanged when it cannot be resolved.
+func sourcePath(file string) string {
+	file = filepath.ToSlash(filepath.Clean(file))
+
+	if rel, ok := trimDir(file, moduleCacheDir()); ok {
+		return unescapeModulePath(trimModuleVersion(rel))
+	}
+
+	if idx := strings.LastIndex(file, "/vendor/"); idx >= 0 {
+		return file[idx+len("/vendor/"):]
+	}
+
+	if rel, ok := trimDir(file, path.Join(filepath.ToSlash(runtime.GOROOT()), "src")); ok {
+		return rel
+	}
+
+	if dir, modPath := findModule(path.Dir(file)); modPath != "" {
+		rel, _ := trimDir(file, dir)
+
+		return path.Join(modPath, rel)
+	}
+
+	if rel, ok := trimDir(file, path.Join(filepath.ToSlash(getGoPath()), "src")); ok {
+		return rel
+	}
+
+	return file
+}
+
+// trimDir returns the file path relative to dir.
+func trimDir(file, dir string) (string, bool) {
+	if dir == "" || dir == "." || !strings.HasPrefix(file, dir+"/") {
+		return "", false
+	}
+
+	return file[len(dir)+1:], true
+}
+
+func moduleCacheDir() string {
+	dir := os.Getenv("GOMODCACHE")
+	if dir == "" {
+		goPath := filepath.SplitList(getGoPath())
+		if len(goPath) == 0 {
+			return ""
+		}
+		dir = filepath.Join(goPath[0], "pkg", "mod")
+	}
+
+	return filepath.ToSlash(filepath.Clean(dir))
+}
+
+// trimModuleVersion removes the version of the module cache directory:
+// "github.com/go-chi/chi/v5@v5.0.7/mux.go" becomes "github.com/go-chi/chi/v5/mux.go".
+func trimModuleVersion(rel string) string {
+	at := strings.IndexByte(rel, '@')
+	if at < 0 {
+		return rel
+	}
+
+	end := strings.IndexByte(rel[at:], '/')
+	if end < 0 {
+		return rel[:at]
+	}
+
+	return rel[:at] + rel[at+end:]
+}
+
+// unescapeModulePath reverts the case-encoding of the module cache:
+// "github.com/!burnt!sushi" becomes "github.com/BurntSushi".
+func unescapeModulePath(rel string) string {
+	if !strings.Contains(rel, "!") {
+		return rel
+	}
+
+	var b strings.Builder
+	upper := false
+	for _, r := range rel {
+		switch {
+		case r == '!':
+			upper = true
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// findModule returns the directory of the go.mod enclosing dir and its module path.
+func findModule(dir string) (modDir, modPath string) {
+	for {
+		if v, ok := modulePaths.Load(dir); ok {
+			if modPath = v.(string); modPath != "" {
+				return dir, modPath
+			}
+		} else {
+			modPath = readModulePath(path.Join(dir, "go.mod"))
+			modulePaths.Store(dir, modPath)
+			if modPath != "" {
+				return dir, modPath
+			}
+		}
+
+		parent := path.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// readModulePath returns the path of the module directive of a go.mod file.
+func readModulePath(goMod string) string {
+	data, err := os.ReadFile(filepath.FromSlash(goMod))
+	if err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,68 @@
+package docgen
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func Test_sourcePath(t *testing.T) {
+	tmp := filepath.ToSlash(t.TempDir())
+	t.Setenv("GOMODCACHE", tmp+"/modcache")
+
+	svc := tmp + "/work/svc"
+	if err := os.MkdirAll(svc+"/handlers", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	goMod := "// Service\nmodule example.com/svc // comment\n\ngo 1.18\n"
+	if err := os.WriteFile(svc+"/go.mod", []byte(goMod), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		file string
+		want string
+	}{{
+		name: "main module",
+		file: svc + "/handlers/articles.go",
+		want: "example.com/svc/handlers/articles.go",
+	}, {
+		name: "module cache",
+		file: tmp + "/modcache/github.com/go-chi/chi/v5@v5.0.7/middleware/logger.go",
+		want: "github.com/go-chi/chi/v5/middleware/logger.go",
+	}, {
+		name: "module cache case-encoding",
+		file: tmp + "/modcache/github.com/!burnt!sushi/toml@v1.2.0/decode.go",
+		want: "github.com/BurntSushi/toml/decode.go",
+	}, {
+		name: "vendor",
+		file: svc + "/vendor/github.com/go-chi/chi/v5/mux.go",
+		want: "github.com/go-chi/chi/v5/mux.go",
+	}, {
+		name: "standard library",
+		file: filepath.ToSlash(runtime.GOROOT()) + "/src/net/http/server.go",
+		want: "net/http/server.go",
+	}, {
+		name: "outside any module",
+		file: "/nowhere/main.go",
+		want: "/nowhere/main.go",
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := sourcePath(c.file); got != c.want {
+				t.Errorf("sourcePath() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func Test_GetFuncInfo_modulePath(t *testing.T) {
+	fi := GetFuncInfo(BuildDoc)
+
+	if want := "github.com/teal-finance/docgen-yes/builder.go"; fi.File != want {
+		t.Errorf("GetFuncInfo().File = %v, want %v", fi.File, want)
+	}
+}
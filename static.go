@@ -0,0 +1,985 @@
+package docgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// BuildDocFromSource builds the Doc of a chi router without running the application,
+// so the documentation can be produced in CI from the source code alone.
+//
+// The package pkg, an import path or a directory relative to dir, is listed by the go command,
+// honoring the build flags of GOFLAGS, and type-checked against the export data of its
+// dependencies. The chi calls (NewRouter, Use, With, Group, Route, Mount, Get, Post...)
+// of the function fn are interpreted to reconstruct the routes, following the calls
+// to the functions of the same module. fn is a function of pkg such as "NewRouter"
+// or "main": the router it returns is documented, else the router received as parameter,
+// else the first router created and not mounted into another one.
+//
+// The values unknown before running, such as a pattern computed at runtime,
+// are skipped: the Doc is the same as BuildDoc for the routers built statically.
+func BuildDocFromSource(dir, pkg, fn string) (Doc, error) {
+	sa := newStaticAnalyzer(dir)
+
+	sp, err := sa.load(pkg, dir)
+	if err != nil {
+		return Doc{}, err
+	}
+	sa.modulePath = sa.modulePrefix(sp)
+
+	decl := sp.funcs[fn]
+	if decl == nil {
+		return Doc{}, fmt.Errorf("docgen: function %s not found in %s", fn, sp.path)
+	}
+
+	root := sa.newRouterParams(sp, decl)
+	if ret := sa.evalFunc(sp, decl.Body); ret != nil {
+		root = ret
+	}
+	if root == nil {
+		root = sa.firstRouter()
+	}
+	if root == nil {
+		return Doc{}, fmt.Errorf("docgen: no chi router built by %s.%s", sp.path, fn)
+	}
+
+	return Doc{Router: root.router.docRouter(""), Schemas: nil}, nil
+}
+
+// chiPath is the import path of chi, without the major version suffix.
+const chiPath = "github.com/go-chi/chi"
+
+// staticAnalyzer interprets the chi calls of the loaded packages.
+type staticAnalyzer struct {
+	*exportLoader
+
+	dir        string
+	modulePath string
+
+	pkgs     map[string]*sourcePackage // import path : package with its AST
+	files    map[string]*ast.File      // file name : AST with comments of the imported packages
+	values   map[types.Object]*routerView
+	created  []*staticRouter
+	mounted  map[*staticRouter]bool
+	visiting map[*ast.FuncDecl]bool // avoid infinite recursions
+}
+
+func newStaticAnalyzer(dir string) *staticAnalyzer {
+	return &staticAnalyzer{
+		exportLoader: newExportLoader(token.NewFileSet()),
+		dir:          dir,
+		modulePath:   "",
+		pkgs:         map[string]*sourcePackage{},
+		files:        map[string]*ast.File{},
+		values:       map[types.Object]*routerView{},
+		created:      nil,
+		mounted:      map[*staticRouter]bool{},
+		visiting:     map[*ast.FuncDecl]bool{},
+	}
+}
+
+// sourcePackage is a type-checked package with its AST.
+type sourcePackage struct {
+	path     string
+	files    []*ast.File
+	info     *types.Info
+	funcs    map[string]*ast.FuncDecl      // function or "Type.Method" : declaration
+	closures map[*ast.FuncLit]string       // closure : name given by the compiler, e.g. "NewRouter.func1"
+	globals  map[types.Object]*ast.FuncLit // package-level variable : closure of its declaration
+}
+
+// load parses and type-checks the package pkg (import path or directory relative to srcDir),
+// its imports being read from the export data compiled by the go command.
+func (sa *staticAnalyzer) load(pkg, srcDir string) (*sourcePackage, error) {
+	if sp, ok := sa.pkgs[pkg]; ok {
+		return sp, nil
+	}
+
+	lp, ok := sa.listed[pkg]
+	if !ok {
+		var err error
+		if lp, err = sa.list(pkg, srcDir); err != nil {
+			return nil, err
+		}
+	}
+	if len(lp.GoFiles) == 0 {
+		if lp.Error != nil {
+			return nil, fmt.Errorf("docgen: %s", lp.Error.Err)
+		}
+
+		return nil, fmt.Errorf("docgen: no Go file in %s", lp.Dir)
+	}
+
+	importPath := lp.ImportPath
+	if importPath == "" || build.IsLocalImport(importPath) || strings.HasPrefix(importPath, "_") {
+		importPath = path.Dir(sourcePath(filepath.ToSlash(filepath.Join(lp.Dir, lp.GoFiles[0]))))
+	}
+	if sp, ok := sa.pkgs[importPath]; ok {
+		return sp, nil
+	}
+
+	sp := &sourcePackage{
+		path:  importPath,
+		files: make([]*ast.File, 0, len(lp.GoFiles)),
+		info: &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+		},
+		funcs:    map[string]*ast.FuncDecl{},
+		closures: map[*ast.FuncLit]string{},
+		globals:  map[types.Object]*ast.FuncLit{},
+	}
+
+	for _, name := range lp.GoFiles {
+		file := filepath.Join(lp.Dir, name)
+
+		f, err := parser.ParseFile(sa.fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("docgen: %w", err)
+		}
+		sp.files = append(sp.files, f)
+		sa.files[file] = f
+	}
+
+	// keep going on type errors: the routes do not need a flawless package
+	conf := types.Config{Importer: importerMap{sa.importer, lp.ImportMap}, Error: func(error) {}}
+	if _, err := conf.Check(importPath, sa.fset, sp.files, sp.info); err != nil && len(sp.info.Defs) == 0 {
+		return nil, fmt.Errorf("docgen: %w", err)
+	}
+
+	sp.index()
+
+	sa.pkgs[pkg] = sp
+	sa.pkgs[importPath] = sp
+
+	return sp, nil
+}
+
+// index registers the function declarations of the files and names their closures
+// as the compiler does: "Func.func1", "Func.func1.1", "(*Type).Method.func2"...
+// The closures of the package-level declarations are numbered across the files,
+// in the order of the files given to the compiler.
+func (sp *sourcePackage) index() {
+	globals := 0
+
+	for _, f := range sp.files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				sp.indexGlobals(decl)
+				ast.Inspect(decl, func(n ast.Node) bool {
+					if lit, ok := n.(*ast.FuncLit); ok {
+						globals++
+						sp.nameClosures(lit, globalClosurePrefix+strconv.Itoa(globals))
+
+						return false
+					}
+
+					return true
+				})
+
+				continue
+			}
+
+			name := funcDeclName(fd)
+			sp.funcs[strings.NewReplacer("(", "", ")", "", "*", "").Replace(name)] = fd
+
+			if fd.Body != nil {
+				sp.nameClosures(fd.Body, name+".func")
+			}
+		}
+	}
+}
+
+// indexGlobals registers the package-level variables declared with a closure, e.g. "var ping = func(...) {...}".
+func (sp *sourcePackage) indexGlobals(decl ast.Decl) {
+	gd, ok := decl.(*ast.GenDecl)
+	if !ok {
+		return
+	}
+
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Names) != len(vs.Values) {
+			continue
+		}
+
+		for i, value := range vs.Values {
+			if lit, ok := value.(*ast.FuncLit); ok {
+				if obj := sp.info.Defs[vs.Names[i]]; obj != nil {
+					sp.globals[obj] = lit
+				}
+			}
+		}
+	}
+}
+
+// globalClosurePrefix is the name of the closures of the package-level declarations
+// without their number, which depends on the compiler: "glob..func" or "init.func".
+// It is read from a closure of this package, built by the same compiler as the application.
+var globalClosurePrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(closureProbe).Pointer()).Name()
+	name = name[strings.LastIndexByte(name, '/')+1:] // e.g. "docgen-yes.init.func1"
+	name = name[strings.IndexByte(name, '.')+1:]     // "init.func1"
+
+	return strings.TrimRight(name, "0123456789")
+}()
+
+var closureProbe = func() {}
+
+// nameClosures names the closures directly nested in node, their number appended to prefix.
+func (sp *sourcePackage) nameClosures(node ast.Node, prefix string) {
+	if lit, ok := node.(*ast.FuncLit); ok {
+		sp.closures[lit] = prefix
+		node, prefix = lit.Body, prefix+"."
+	}
+
+	count := 0
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+
+		count++
+		sp.nameClosures(lit, prefix+strconv.Itoa(count))
+
+		return false
+	})
+}
+
+// funcDeclName returns the name of a function as in the runtime, e.g. "(*Type).Method".
+func funcDeclName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+
+	recv := fd.Recv.List[0].Type
+	if idx, ok := recv.(*ast.IndexExpr); ok { // generic receiver
+		recv = idx.X
+	}
+
+	if star, ok := recv.(*ast.StarExpr); ok {
+		return "(*" + types.ExprString(star.X) + ")." + fd.Name.Name
+	}
+
+	return types.ExprString(recv) + "." + fd.Name.Name
+}
+
+// modulePrefix returns the module path of a package, the calls to its functions are followed.
+func (sa *staticAnalyzer) modulePrefix(sp *sourcePackage) string {
+	file := filepath.ToSlash(sa.fset.Position(sp.files[0].Pos()).Filename)
+	if _, modPath := findModule(path.Dir(file)); modPath != "" {
+		return modPath
+	}
+
+	return sp.path
+}
+
+// inModule reports whether the package pkgPath belongs to the module of the analyzed package.
+func (sa *staticAnalyzer) inModule(pkgPath string) bool {
+	return pkgPath == sa.modulePath || strings.HasPrefix(pkgPath, sa.modulePath+"/")
+}
+
+// staticRouter is a chi.Mux reconstructed from the source code.
+type staticRouter struct {
+	middlewares []DocMiddleware
+	routes      map[string]*staticRoute
+}
+
+type staticRoute struct {
+	handlers DocHandlers
+	sub      *staticRouter
+	inline   []DocMiddleware // of the mount point of sub
+}
+
+// routerView is the value of a chi.Router expression: a router,
+// possibly seen through the inline middlewares of With or Group.
+type routerView struct {
+	router   *staticRouter
+	inline   []DocMiddleware
+	isInline bool
+}
+
+func (sa *staticAnalyzer) newRouter() *routerView {
+	r := &staticRouter{middlewares: []DocMiddleware{}, routes: map[string]*staticRoute{}}
+	sa.created = append(sa.created, r)
+
+	return &routerView{router: r, inline: nil, isInline: false}
+}
+
+// firstRouter returns the first router created and not mounted into another one.
+func (sa *staticAnalyzer) firstRouter() *routerView {
+	for _, r := range sa.created {
+		if !sa.mounted[r] {
+			return &routerView{router: r, inline: nil, isInline: false}
+		}
+	}
+
+	return nil
+}
+
+// newRouterParams binds a new router to the chi.Router parameters of a function
+// and returns the first one, nil if none.
+func (sa *staticAnalyzer) newRouterParams(sp *sourcePackage, fd *ast.FuncDecl) *routerView {
+	var first *routerView
+
+	for _, field := range fd.Type.Params.List {
+		for _, name := range field.Names {
+			obj := sp.info.Defs[name]
+			if obj == nil || !isChiRouter(obj.Type()) {
+				continue
+			}
+
+			view := sa.newRouter()
+			sa.values[obj] = view
+			if first == nil {
+				first = view
+			}
+		}
+	}
+
+	return first
+}
+
+// isChiRouter reports whether t is a chi.Mux, chi.Router or chi.Routes.
+func isChiRouter(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	switch named.Obj().Name() {
+	case "Mux", "Router", "Routes":
+		return strings.HasPrefix(named.Obj().Pkg().Path(), chiPath)
+	default:
+		return false
+	}
+}
+
+// evalFunc interprets the statements of a function body
+// and returns the router it returns, nil if none.
+func (sa *staticAnalyzer) evalFunc(sp *sourcePackage, body *ast.BlockStmt) *routerView {
+	var ret *routerView
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// closures passed to Route or Group are interpreted with their router,
+			// the other ones, such as a goroutine, are interpreted in place
+			return true
+
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				view := sa.eval(sp, rhs)
+				if view != nil && len(n.Lhs) == len(n.Rhs) {
+					sa.bind(sp, n.Lhs[i], view)
+				}
+			}
+
+			return false
+
+		case *ast.ValueSpec:
+			for i, value := range n.Values {
+				view := sa.eval(sp, value)
+				if view != nil && i < len(n.Names) {
+					sa.bind(sp, n.Names[i], view)
+				}
+			}
+
+			return false
+
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				if view := sa.eval(sp, result); view != nil && ret == nil {
+					ret = view
+				}
+			}
+
+			return false
+
+		case *ast.CallExpr:
+			sa.eval(sp, n)
+
+			return false
+
+		default:
+			return true
+		}
+	})
+
+	return ret
+}
+
+// bind stores the router of a variable or a struct field.
+func (sa *staticAnalyzer) bind(sp *sourcePackage, lhs ast.Expr, view *routerView) {
+	var obj types.Object
+
+	switch lhs := lhs.(type) {
+	case *ast.Ident:
+		if obj = sp.info.Defs[lhs]; obj == nil {
+			obj = sp.info.Uses[lhs]
+		}
+	case *ast.SelectorExpr:
+		obj = sp.info.Uses[lhs.Sel]
+	}
+
+	if obj == nil {
+		return
+	}
+
+	sa.values[obj] = view
+}
+
+// eval interprets an expression and returns its router, nil if it is not a router.
+func (sa *staticAnalyzer) eval(sp *sourcePackage, expr ast.Expr) *routerView {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return sa.eval(sp, e.X)
+
+	case *ast.UnaryExpr:
+		return sa.eval(sp, e.X)
+
+	case *ast.Ident:
+		return sa.values[sp.info.Uses[e]]
+
+	case *ast.SelectorExpr:
+		if _, isField := sp.info.Selections[e]; isField {
+			return sa.values[sp.info.Uses[e.Sel]]
+		}
+
+		return nil
+
+	case *ast.CallExpr:
+		return sa.evalCall(sp, e)
+
+	case *ast.CompositeLit:
+		// struct fields holding a router, e.g. &Server{router: chi.NewRouter()}
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if view := sa.eval(sp, kv.Value); view != nil {
+					sa.bind(sp, kv.Key, view)
+				}
+			}
+		}
+
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func (sa *staticAnalyzer) evalCall(sp *sourcePackage, call *ast.CallExpr) *routerView {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if s, ok := sp.info.Selections[sel]; ok && s.Kind() == types.MethodVal && isChiRouter(s.Recv()) {
+			if view := sa.eval(sp, sel.X); view != nil {
+				return sa.evalMethod(sp, view, sel.Sel.Name, call.Args)
+			}
+
+			return nil
+		}
+	}
+
+	fn := callee(sp.info, call)
+	if fn == nil {
+		return nil
+	}
+
+	if fn.Pkg() != nil && strings.HasPrefix(fn.Pkg().Path(), chiPath) {
+		if fn.Name() == "NewRouter" || fn.Name() == "NewMux" {
+			return sa.newRouter()
+		}
+
+		return nil
+	}
+
+	return sa.evalCallee(sp, fn, call)
+}
+
+// evalCallee interprets the body of a function of the module called with some routers.
+func (sa *staticAnalyzer) evalCallee(sp *sourcePackage, fn *types.Func, call *ast.CallExpr) *routerView {
+	args := make([]*routerView, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = sa.eval(sp, arg)
+	}
+
+	return sa.evalDecl(fn, args)
+}
+
+// evalDecl interprets the declaration of a function of the module,
+// args are the routers passed as arguments, nil for the other arguments.
+func (sa *staticAnalyzer) evalDecl(fn *types.Func, args []*routerView) *routerView {
+	if fn.Pkg() == nil || !sa.inModule(fn.Pkg().Path()) {
+		return nil
+	}
+
+	calleePkg, err := sa.load(fn.Pkg().Path(), sa.dir)
+	if err != nil {
+		return nil
+	}
+
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		name = receiverName(recv.Type()) + "." + name
+	}
+
+	fd := calleePkg.funcs[name]
+	if fd == nil || fd.Body == nil || sa.visiting[fd] {
+		return nil
+	}
+
+	for _, field := range fd.Type.Params.List {
+		for _, paramName := range field.Names {
+			if len(args) == 0 {
+				break
+			}
+			if obj := calleePkg.info.Defs[paramName]; obj != nil && args[0] != nil {
+				sa.values[obj] = args[0]
+			}
+			args = args[1:]
+		}
+	}
+
+	sa.visiting[fd] = true
+	defer delete(sa.visiting, fd)
+
+	return sa.evalFunc(calleePkg, fd.Body)
+}
+
+func receiverName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+
+	return types.TypeString(t, nil)
+}
+
+// callee returns the function or method called, nil for a conversion or a func value.
+func callee(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := call.Fun
+	if paren, ok := fun.(*ast.ParenExpr); ok {
+		fun = paren.X
+	}
+
+	var obj types.Object
+	switch f := fun.(type) {
+	case *ast.Ident:
+		obj = info.Uses[f]
+	case *ast.SelectorExpr:
+		if s, ok := info.Selections[f]; ok {
+			obj = s.Obj()
+		} else {
+			obj = info.Uses[f.Sel]
+		}
+	case *ast.IndexExpr: // generic function
+		if id, ok := f.X.(*ast.Ident); ok {
+			obj = info.Uses[id]
+		}
+	}
+
+	fn, _ := obj.(*types.Func)
+
+	return fn
+}
+
+// evalMethod interprets a method call on a chi router.
+func (sa *staticAnalyzer) evalMethod(sp *sourcePackage, view *routerView, method string, args []ast.Expr) *routerView {
+	switch method {
+	case "Use":
+		mws := sa.middlewares(sp, args)
+		if view.isInline {
+			view.inline = append(view.inline, mws...)
+		} else {
+			view.router.middlewares = append(view.router.middlewares, mws...)
+		}
+
+		return nil
+
+	case "With":
+		inline := append(append([]DocMiddleware{}, view.inline...), sa.middlewares(sp, args)...)
+
+		return &routerView{router: view.router, inline: inline, isInline: true}
+
+	case "Group":
+		group := &routerView{router: view.router, inline: append([]DocMiddleware{}, view.inline...), isInline: true}
+		if len(args) == 1 {
+			sa.evalFuncArg(sp, args[0], group)
+		}
+
+		return group
+
+	case "Route":
+		if len(args) != 2 {
+			return nil
+		}
+		sub := sa.newRouter()
+		sa.mount(sp, view, args[0], sub)
+		sa.evalFuncArg(sp, args[1], sub)
+
+		return sub
+
+	case "Mount":
+		if len(args) != 2 {
+			return nil
+		}
+		if sub := sa.eval(sp, args[1]); sub != nil {
+			sa.mount(sp, view, args[0], sub)
+		} else if pattern, ok := stringValue(sp.info, args[0]); ok {
+			sa.handle(sp, view, "*", mountPattern(pattern), args[1])
+		}
+
+		return nil
+
+	case "Handle", "HandleFunc":
+		if len(args) == 2 {
+			if pattern, ok := stringValue(sp.info, args[0]); ok {
+				sa.handle(sp, view, "*", pattern, args[1])
+			}
+		}
+
+		return nil
+
+	case "Method", "MethodFunc":
+		if len(args) == 3 {
+			m, okMethod := stringValue(sp.info, args[0])
+			pattern, okPattern := stringValue(sp.info, args[1])
+			if okMethod && okPattern {
+				sa.handle(sp, view, strings.ToUpper(m), pattern, args[2])
+			}
+		}
+
+		return nil
+
+	case "Connect", "Delete", "Get", "Head", "Options", "Patch", "Post", "Put", "Trace":
+		if len(args) == 2 {
+			if pattern, ok := stringValue(sp.info, args[0]); ok {
+				sa.handle(sp, view, strings.ToUpper(method), pattern, args[1])
+			}
+		}
+
+		return nil
+
+	default: // NotFound, MethodNotAllowed...
+		return nil
+	}
+}
+
+// evalFuncArg interprets the function passed to Route or Group with its router.
+func (sa *staticAnalyzer) evalFuncArg(sp *sourcePackage, arg ast.Expr, view *routerView) {
+	switch fn := arg.(type) {
+	case *ast.FuncLit:
+		for _, field := range fn.Type.Params.List {
+			for _, name := range field.Names {
+				if obj := sp.info.Defs[name]; obj != nil {
+					sa.values[obj] = view
+				}
+			}
+		}
+		sa.evalFunc(sp, fn.Body)
+
+	default: // a named function, e.g. r.Route("/articles", articleRoutes)
+		if fn := funcObject(sp.info, arg); fn != nil {
+			sa.evalDecl(fn, []*routerView{view})
+		}
+	}
+}
+
+// funcObject returns the function referenced by an expression, nil if none.
+func funcObject(info *types.Info, expr ast.Expr) *types.Func {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return funcObject(info, e.X)
+	case *ast.Ident:
+		fn, _ := info.Uses[e].(*types.Func)
+
+		return fn
+	case *ast.SelectorExpr:
+		if s, ok := info.Selections[e]; ok {
+			fn, _ := s.Obj().(*types.Func)
+
+			return fn
+		}
+		fn, _ := info.Uses[e.Sel].(*types.Func)
+
+		return fn
+	default:
+		return nil
+	}
+}
+
+// mount adds the sub-router at pattern+"/*" as chi.Mux.Mount does.
+func (sa *staticAnalyzer) mount(sp *sourcePackage, view *routerView, patternExpr ast.Expr, sub *routerView) {
+	pattern, ok := stringValue(sp.info, patternExpr)
+	if !ok {
+		return
+	}
+
+	rt := view.router.route(mountPattern(pattern))
+	rt.sub = sub.router
+	rt.inline = append([]DocMiddleware(nil), view.inline...)
+	sa.mounted[sub.router] = true
+}
+
+func mountPattern(pattern string) string {
+	return strings.TrimSuffix(pattern, "/") + "/*"
+}
+
+// handle adds the handler of a method, with the inline middlewares of the view.
+func (sa *staticAnalyzer) handle(sp *sourcePackage, view *routerView, method, pattern string, handler ast.Expr) {
+	view.router.route(pattern).handlers[method] = DocHandler{
+		Middlewares: append([]DocMiddleware{}, view.inline...),
+		Method:      method,
+		Params:      nil, // set by docRouter from the full pattern
+		FuncInfo:    sa.funcInfo(sp, handler),
+	}
+}
+
+func (r *staticRouter) route(pattern string) *staticRoute {
+	rt := r.routes[pattern]
+	if rt == nil {
+		rt = &staticRoute{handlers: DocHandlers{}, sub: nil, inline: nil}
+		r.routes[pattern] = rt
+	}
+
+	return rt
+}
+
+// docRouter converts the router into a DocRouter, as BuildDocRouter does for a chi.Routes.
+func (r *staticRouter) docRouter(parentPattern string) DocRouter {
+	dr := DocRouter{
+		InlineMiddlewares: nil,
+		Middlewares:       r.middlewares,
+		Routes:            DocRoutes{},
+	}
+
+	for pat, rt := range r.routes {
+		pattern := JoinPattern(parentPattern, pat)
+
+		drt := DocRoute{
+			Pattern:  pat,
+			Params:   ParsePattern(pat),
+			Handlers: DocHandlers{},
+			Router: &DocRouter{
+				InlineMiddlewares: nil,
+				Middlewares:       []DocMiddleware{},
+				Routes:            DocRoutes{},
+			},
+		}
+
+		if rt.sub != nil {
+			drt.Params = ParsePattern(strings.TrimSuffix(pat, "/*"))
+			sub := rt.sub.docRouter(pattern)
+			sub.InlineMiddlewares = rt.inline
+			drt.Router = &sub
+		} else {
+			for method, dh := range rt.handlers {
+				dh.Params = ParsePattern(pattern)
+				drt.Handlers[method] = dh
+			}
+		}
+
+		dr.Routes[pat] = drt
+	}
+
+	return dr
+}
+
+func (sa *staticAnalyzer) middlewares(sp *sourcePackage, args []ast.Expr) []DocMiddleware {
+	mws := make([]DocMiddleware, 0, len(args))
+	for _, arg := range args {
+		mws = append(mws, DocMiddleware{FuncInfo: sa.funcInfo(sp, arg)})
+	}
+
+	return mws
+}
+
+// stringValue returns the value of a constant string expression.
+func stringValue(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// funcInfo describes the function of a handler or middleware expression as GetFuncInfo does.
+func (sa *staticAnalyzer) funcInfo(sp *sourcePackage, expr ast.Expr) FuncInfo {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return sa.funcInfo(sp, e.X)
+
+	case *ast.FuncLit:
+		return sa.closureInfo(sp, e)
+
+	case *ast.CallExpr:
+		// conversion such as http.HandlerFunc(h)
+		if tv, ok := sp.info.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			return sa.funcInfo(sp, e.Args[0])
+		}
+
+		// constructor such as middleware.Timeout(time.Minute)
+		if fn := callee(sp.info, e); fn != nil {
+			return sa.declInfo(fn)
+		}
+
+	default:
+		if fn := funcObject(sp.info, expr); fn != nil {
+			return sa.declInfo(fn)
+		}
+
+		// package-level variable declared with a closure
+		if owner, lit := sa.globalClosure(sp, expr); lit != nil {
+			return sa.closureInfo(owner, lit)
+		}
+	}
+
+	return FuncInfo{
+		Pkg:          "",
+		Func:         types.ExprString(expr),
+		Comment:      "",
+		File:         "",
+		ASTFile:      nil,
+		Line:         0,
+		Anonymous:    false,
+		Unresolvable: true,
+		Annotations:  nil,
+	}
+}
+
+// declInfo describes a declared function or method from its doc comment.
+func (sa *staticAnalyzer) declInfo(fn *types.Func) FuncInfo {
+	pos := sa.fset.Position(fn.Pos())
+	pkgPath := ""
+	if fn.Pkg() != nil {
+		pkgPath = fn.Pkg().Path()
+	}
+
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		if _, isPtr := recv.Type().(*types.Pointer); isPtr {
+			name = "(*" + receiverName(recv.Type()) + ")." + name
+		} else {
+			name = receiverName(recv.Type()) + "." + name
+		}
+	}
+
+	fi := FuncInfo{
+		Pkg:          pkgPath,
+		Func:         name,
+		Comment:      "",
+		File:         sourcePath(filepath.ToSlash(pos.Filename)),
+		ASTFile:      nil,
+		Line:         pos.Line,
+		Anonymous:    false,
+		Unresolvable: fn.Pkg() != nil && fn.Pkg().Name() == "chi", // as GetFuncInfo
+		Annotations:  nil,
+	}
+
+	if fi.Unresolvable || !pos.IsValid() {
+		return fi
+	}
+
+	if f := sa.parseFile(pos.Filename); f != nil {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if ok && fd.Name.Name == fn.Name() && sa.fset.Position(fd.Name.Pos()).Line == pos.Line {
+				fi.Line = sa.fset.Position(fd.Pos()).Line
+				fi.Comment = fd.Doc.Text()
+			}
+		}
+	}
+
+	fi.Comment, fi.Annotations = ParseAnnotations(fi.Comment)
+
+	return fi
+}
+
+// globalClosure returns the closure declaring a package-level variable of a loaded package,
+// with the package, nil if none.
+func (sa *staticAnalyzer) globalClosure(sp *sourcePackage, expr ast.Expr) (*sourcePackage, *ast.FuncLit) {
+	var obj types.Object
+	switch e := expr.(type) {
+	case *ast.Ident:
+		obj = sp.info.Uses[e]
+	case *ast.SelectorExpr:
+		obj = sp.info.Uses[e.Sel]
+	}
+
+	v, ok := obj.(*types.Var)
+	if !ok || v.Pkg() == nil {
+		return nil, nil
+	}
+
+	owner, ok := sa.pkgs[v.Pkg().Path()]
+	if !ok {
+		return nil, nil
+	}
+
+	return owner, owner.globals[v]
+}
+
+// closureInfo describes a function literal, the comment is the one on the line above.
+func (sa *staticAnalyzer) closureInfo(sp *sourcePackage, lit *ast.FuncLit) FuncInfo {
+	pos := sa.fset.Position(lit.Pos())
+
+	fi := FuncInfo{
+		Pkg:          sp.path,
+		Func:         sp.closures[lit],
+		Comment:      "",
+		File:         sourcePath(filepath.ToSlash(pos.Filename)),
+		ASTFile:      nil,
+		Line:         pos.Line,
+		Anonymous:    true,
+		Unresolvable: false,
+		Annotations:  nil,
+	}
+
+	if f := sa.files[pos.Filename]; f != nil {
+		for _, cmt := range f.Comments {
+			if sa.fset.Position(cmt.End()).Line+1 == pos.Line {
+				fi.Comment = cmt.Text()
+			}
+		}
+	}
+
+	fi.Comment, fi.Annotations = ParseAnnotations(fi.Comment)
+
+	return fi
+}
+
+// parseFile returns the AST with comments of a source file, nil on error.
+func (sa *staticAnalyzer) parseFile(filename string) *ast.File {
+	if f, ok := sa.files[filename]; ok {
+		return f
+	}
+
+	f, err := parser.ParseFile(sa.fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		f = nil
+	}
+	sa.files[filename] = f
+
+	return f
+}
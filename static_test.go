@@ -0,0 +1,145 @@
+package docgen_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/teal-finance/docgen-yes"
+	"github.com/teal-finance/docgen-yes/testdata/articles"
+	"github.com/teal-finance/docgen-yes/testdata/closures"
+)
+
+func TestBuildDocFromSource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pkg    string
+		router chi.Router
+	}{
+		{pkg: "./testdata/articles", router: articles.Router()},
+		{pkg: "./testdata/closures", router: closures.Router()}, // closures numbered across two files
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.pkg, func(t *testing.T) {
+			t.Parallel()
+
+			live, err := docgen.BuildDoc(c.router)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			static, err := docgen.BuildDocFromSource(".", c.pkg, "Router")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, got := docJSON(t, live), docJSON(t, static)
+			if got != want {
+				t.Errorf("BuildDocFromSource() =\n%s\nwant the Doc of the running router:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildDocFromSource_errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		pkg  string
+		fn   string
+		want string
+	}{{
+		name: "unknown package",
+		pkg:  "./testdata/missing",
+		fn:   "Router",
+		want: "docgen: ",
+	}, {
+		name: "unknown function",
+		pkg:  "./testdata/articles",
+		fn:   "NewRouter",
+		want: "function NewRouter not found",
+	}, {
+		name: "no router",
+		pkg:  "./testdata/articles",
+		fn:   "ListArticles",
+		want: "no chi router built",
+	}}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := docgen.BuildDocFromSource(".", c.pkg, c.fn)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("BuildDocFromSource() error = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestBuildDocFromSource_otherModule(t *testing.T) {
+	t.Parallel()
+
+	sum, err := os.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// example.com/api-client shares the prefix of the module example.com/api
+	tmp := t.TempDir()
+	files := map[string]string{
+		"api/go.mod": "module example.com/api\n\ngo 1.18\n\n" +
+			"require (\n\tgithub.com/go-chi/chi/v5 v5.0.7\n\texample.com/api-client v0.0.0\n)\n\n" +
+			"replace example.com/api-client => ../api-client\n",
+		"api/go.sum": string(sum),
+		"api/router.go": "package api\n\nimport (\n\t\"net/http\"\n\n\t\"example.com/api-client\"\n" +
+			"\t\"github.com/go-chi/chi/v5\"\n)\n\n" +
+			"func Ping(w http.ResponseWriter, r *http.Request) {}\n\n" +
+			"func Router() chi.Router {\n\tr := chi.NewRouter()\n\tr.Get(\"/ping\", Ping)\n\tclient.Routes(r)\n\n\treturn r\n}\n",
+		"api-client/go.mod": "module example.com/api-client\n\ngo 1.18\n\nrequire github.com/go-chi/chi/v5 v5.0.7\n",
+		"api-client/go.sum": string(sum),
+		"api-client/client.go": "package client\n\nimport (\n\t\"net/http\"\n\n\t\"github.com/go-chi/chi/v5\"\n)\n\n" +
+			"func Routes(r chi.Router) {\n\tr.Get(\"/client\", func(w http.ResponseWriter, r *http.Request) {})\n}\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	doc, err := docgen.BuildDocFromSource(filepath.Join(tmp, "api"), ".", "Router")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(doc.Router.Routes.Patterns(), ","); got != "/ping" {
+		t.Errorf("routes = %s, want /ping without the calls into example.com/api-client", got)
+	}
+}
+
+// docJSON serializes a Doc, FuncInfo.ASTFile excluded.
+func docJSON(t *testing.T, doc docgen.Doc) string {
+	t.Helper()
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
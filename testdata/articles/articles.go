@@ -0,0 +1,85 @@
+// Package articles is a small API used to test the JSON Schema resolution.
+package articles
+
+import (
+	"net/http"
+	"time"
+)
+
+// Article is a blog post.
+type Article struct {
+	Audit
+
+	ID       int               `json:"id"`
+	Title    string            `json:"title"`
+	Tags     []string          `json:"tags,omitempty"`
+	Author   *Author           `json:"author"`
+	Related  []Article         `json:"related,omitempty"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	Score    float64           `json:"score,string"`
+	internal bool
+	Ignored  string `json:"-"`
+}
+
+// Audit is embedded in Article, its fields are promoted.
+type Audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Author of an Article.
+type Author struct {
+	Name string
+}
+
+// Comment on an Article, the fields of the unexported base are promoted.
+type Comment struct {
+	base
+
+	Text string `json:"text"`
+}
+
+type base struct {
+	ID      int `json:"id"`
+	private int
+}
+
+// Reply shadows the id of the embedded Comment.
+type Reply struct {
+	Comment
+
+	ID string `json:"id"`
+}
+
+// Draft embeds two structs having a text at the same depth, dropped as by encoding/json.
+type Draft struct {
+	Comment
+	Note
+}
+
+// Note of a Draft.
+type Note struct {
+	Text string `json:"text"`
+}
+
+// Node embeds itself.
+type Node struct {
+	*Node
+
+	Name string `json:"name"`
+}
+
+// ArticleRequest creates an Article.
+type ArticleRequest struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// CreateArticle creates an Article.
+// @body application/json ArticleRequest
+// @response 201 application/json Article
+// @response 400 application/json Missing
+func CreateArticle(w http.ResponseWriter, r *http.Request) {}
+
+// ListArticles returns all the Articles.
+// @response 200 application/json []Article
+func ListArticles(w http.ResponseWriter, r *http.Request) {}
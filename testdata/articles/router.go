@@ -0,0 +1,61 @@
+package articles
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Router builds the routes of the articles API.
+func Router() chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+
+	// Ping checks the service is up.
+	r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+
+	r.Route("/articles", func(r chi.Router) {
+		r.With(Paginate).Get("/", ListArticles)
+		r.Post("/", CreateArticle)
+
+		r.Route("/{articleID:[0-9]+}", articleRoutes)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.NoCache)
+		r.Handle("/feed", http.HandlerFunc(ListArticles))
+	})
+
+	r.With(middleware.NoCache).Mount("/admin", adminRouter())
+	r.Method("PATCH", "/tags", http.HandlerFunc(ListArticles))
+
+	return r
+}
+
+func articleRoutes(r chi.Router) {
+	r.Use(ArticleCtx)
+	r.Get("/", GetArticle)
+}
+
+func adminRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Use(AdminOnly)
+	r.Get("/", ListArticles)
+
+	return r
+}
+
+// GetArticle returns the Article of the URL parameter.
+// @param articleID int Identifier of the Article
+// @response 200 application/json Article
+func GetArticle(w http.ResponseWriter, r *http.Request) {}
+
+// Paginate reads the page parameters of a listing.
+func Paginate(next http.Handler) http.Handler { return next }
+
+// ArticleCtx loads the Article of the URL parameter.
+func ArticleCtx(next http.Handler) http.Handler { return next }
+
+// AdminOnly restricts the routes to the administrators.
+func AdminOnly(next http.Handler) http.Handler { return next }
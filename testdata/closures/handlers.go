@@ -0,0 +1,6 @@
+package closures
+
+import "net/http"
+
+// list is the first package-level closure of the package.
+var list = func(w http.ResponseWriter, r *http.Request) {}
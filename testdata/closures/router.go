@@ -0,0 +1,21 @@
+// Package closures declares its handlers as package-level closures in two files,
+// the compiler numbers them across the files.
+package closures
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Router builds the routes of the closures.
+func Router() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", list)
+	r.Get("/ping", ping)
+
+	return r
+}
+
+// ping is the second package-level closure of the package.
+var ping = func(w http.ResponseWriter, r *http.Request) {}
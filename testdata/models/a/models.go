@@ -0,0 +1,7 @@
+// Package models declares an Article, as the package models of the b directory.
+package models
+
+// Article of the a models.
+type Article struct {
+	Title string `json:"title"`
+}
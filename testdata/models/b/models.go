@@ -0,0 +1,7 @@
+// Package models declares an Article, as the package models of the a directory.
+package models
+
+// Article of the b models.
+type Article struct {
+	Body string `json:"body"`
+}
@@ -5,53 +5,37 @@ import (
 	"os"
 )
 
-func copyDocRouter(dr DocRouter) DocRouter {
-	var (
-		cloneRouter func(dr DocRouter) DocRouter
-		cloneRoutes func(drt DocRoutes) DocRoutes
-	)
-
-	cloneRoutes = func(drts DocRoutes) DocRoutes {
-		rts := DocRoutes{}
-
-		for pat, drt := range drts {
-			rt := DocRoute{
-				Pattern:  drt.Pattern,
-				Handlers: map[string]DocHandler{},
-				Router: &DocRouter{
-					Middlewares: []DocMiddleware{},
-					Routes:      map[string]DocRoute{},
-				},
+// routeTree returns the tree of a route with handlers visited by Walk:
+// the routers from the root to the route, with their middlewares and a single route each.
+// The patterns are the ones of the routes leading to the handlers.
+func routeTree(routers []DocRouter, patterns []string, handlers DocHandlers) DocRouter {
+	var tree *DocRouter
+
+	for i := len(routers) - 1; i >= 0; i-- {
+		drt := DocRoute{Pattern: patterns[i], Params: nil, Handlers: DocHandlers{}, Router: tree}
+		if tree == nil {
+			for meth, dh := range handlers {
+				drt.Handlers[meth] = dh
 			}
-			if len(drt.Handlers) > 0 {
-				rt.Handlers = DocHandlers{}
-				for meth, dh := range drt.Handlers {
-					rt.Handlers[meth] = dh
-				}
-			}
-			if drt.Router != nil {
-				rr := cloneRouter(*drt.Router)
-				rt.Router = &rr
-			}
-			rts[pat] = rt
 		}
 
-		return rts
+		tree = &DocRouter{
+			InlineMiddlewares: append([]DocMiddleware(nil), routers[i].InlineMiddlewares...),
+			Middlewares:       append([]DocMiddleware{}, routers[i].Middlewares...),
+			Routes:            DocRoutes{patterns[i]: drt},
+		}
 	}
 
-	cloneRouter = func(dr DocRouter) DocRouter {
-		cr := DocRouter{
-			Middlewares: []DocMiddleware{},
-			Routes:      map[string]DocRoute{},
-		}
-		cr.Middlewares = make([]DocMiddleware, len(dr.Middlewares))
-		copy(cr.Middlewares, dr.Middlewares)
-		cr.Routes = cloneRoutes(dr.Routes)
+	return *tree
+}
 
-		return cr
+// routeKey removes the trailing slash of the full pattern of the "/" route of a sub-router.
+func routeKey(fullPattern, pattern string) string {
+	if pattern == "/" && len(fullPattern) > 1 {
+		return fullPattern[:len(fullPattern)-1]
 	}
 
-	return cloneRouter(dr)
+	return fullPattern
 }
 
 func getGoPath() string {
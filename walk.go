@@ -0,0 +1,190 @@
+package docgen
+
+import (
+	"errors"
+)
+
+// SkipRouter is returned by Visitor.EnterRouter to skip the middlewares and
+// the routes of the router, or by Visitor.VisitRoute to skip the handlers
+// and the sub-router of the route. LeaveRouter is not called for a skipped router.
+var SkipRouter = errors.New("skip this router") //nolint:errname // as filepath.SkipDir
+
+// StopWalk is returned by a Visitor method to stop the walk without error.
+var StopWalk = errors.New("stop the walk") //nolint:errname // as filepath.SkipAll
+
+// WalkState is the position of the Walk in the Doc tree.
+type WalkState struct {
+	// Pattern is the full pattern of the router or route,
+	// empty for the root router, e.g. "/articles/{articleID}".
+	Pattern string
+
+	// Method is the method of the handler, or of the inline middleware.
+	// It is empty for a router and a route.
+	Method string
+
+	// Middlewares is the stack of the middlewares executed before the visited node:
+	// the middlewares of the routers from the root, each after the inline ones
+	// of its mount point, then the inline ones of a handler.
+	Middlewares []DocMiddleware
+
+	// Depth is the number of sub-routers from the root router.
+	Depth int
+}
+
+// Visitor is called by Walk on each node of the Doc tree.
+// See VisitorFuncs to implement only some methods.
+type Visitor interface {
+	EnterRouter(s WalkState, dr DocRouter) error
+	LeaveRouter(s WalkState, dr DocRouter) error
+	VisitRoute(s WalkState, rt DocRoute) error
+	VisitHandler(s WalkState, dh DocHandler) error
+	VisitMiddleware(s WalkState, mw DocMiddleware) error
+}
+
+// VisitorFuncs is a Visitor calling its non-nil funcs.
+type VisitorFuncs struct {
+	EnterRouterFunc     func(s WalkState, dr DocRouter) error
+	LeaveRouterFunc     func(s WalkState, dr DocRouter) error
+	VisitRouteFunc      func(s WalkState, rt DocRoute) error
+	VisitHandlerFunc    func(s WalkState, dh DocHandler) error
+	VisitMiddlewareFunc func(s WalkState, mw DocMiddleware) error
+}
+
+// Walker walks the Doc tree, see Walk.
+type Walker struct {
+	// MethodOrder sorts the handlers of a route, DefaultMethodOrder when nil.
+	MethodOrder []string
+}
+
+// Walk visits the Doc tree depth-first with the DefaultMethodOrder:
+//
+//	EnterRouter(router)
+//	VisitMiddleware(inline middleware of the mount point, then middleware of the router)...
+//	VisitRoute(route), by pattern
+//	  VisitMiddleware(inline middleware of the handler)...
+//	  VisitHandler(handler), by method
+//	  EnterRouter(sub-router)...LeaveRouter(sub-router)
+//	LeaveRouter(router)
+//
+// The DocRoute.Pattern and the DocHandler.Method are set from the keys of the maps.
+// Walk stops on the first error returned by the Visitor, and returns it
+// unless it is StopWalk.
+func Walk(doc Doc, v Visitor) error {
+	return Walker{MethodOrder: nil}.Walk(doc, v)
+}
+
+// Walk visits the Doc tree, see the Walk function.
+func (w Walker) Walk(doc Doc, v Visitor) error {
+	err := w.router(WalkState{Pattern: "", Method: "", Middlewares: []DocMiddleware{}, Depth: 0}, doc.Router, v)
+	if errors.Is(err, StopWalk) || errors.Is(err, SkipRouter) {
+		return nil
+	}
+
+	return err
+}
+
+func (w Walker) router(s WalkState, dr DocRouter, v Visitor) error {
+	if err := v.EnterRouter(s, dr); err != nil {
+		if errors.Is(err, SkipRouter) {
+			return nil
+		}
+
+		return err
+	}
+
+	middlewares := s.Middlewares
+	for _, mw := range dr.middlewares() {
+		if err := v.VisitMiddleware(WalkState{Pattern: s.Pattern, Method: "", Middlewares: middlewares, Depth: s.Depth}, mw); err != nil {
+			return err
+		}
+		middlewares = append(middlewares[:len(middlewares):len(middlewares)], mw)
+	}
+
+	for _, pat := range dr.Routes.Patterns() {
+		rt := dr.Routes[pat]
+		rt.Pattern = pat
+
+		rs := WalkState{Pattern: JoinPattern(s.Pattern, pat), Method: "", Middlewares: middlewares, Depth: s.Depth}
+		if err := v.VisitRoute(rs, rt); err != nil {
+			if errors.Is(err, SkipRouter) {
+				continue
+			}
+
+			return err
+		}
+
+		for _, meth := range rt.Handlers.Methods(w.MethodOrder) {
+			if err := w.handler(rs, meth, rt.Handlers[meth], v); err != nil {
+				return err
+			}
+		}
+
+		if sr := rt.subRouter(); sr != nil {
+			sub := WalkState{Pattern: rs.Pattern, Method: "", Middlewares: middlewares, Depth: s.Depth + 1}
+			if err := w.router(sub, *sr, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return v.LeaveRouter(s, dr)
+}
+
+func (w Walker) handler(s WalkState, method string, dh DocHandler, v Visitor) error {
+	dh.Method = method
+	s.Method = method
+
+	for _, mw := range dh.Middlewares {
+		if err := v.VisitMiddleware(s, mw); err != nil {
+			return err
+		}
+		s.Middlewares = append(s.Middlewares[:len(s.Middlewares):len(s.Middlewares)], mw)
+	}
+
+	return v.VisitHandler(s, dh)
+}
+
+// EnterRouter implements Visitor.
+func (vf VisitorFuncs) EnterRouter(s WalkState, dr DocRouter) error {
+	if vf.EnterRouterFunc == nil {
+		return nil
+	}
+
+	return vf.EnterRouterFunc(s, dr)
+}
+
+// LeaveRouter implements Visitor.
+func (vf VisitorFuncs) LeaveRouter(s WalkState, dr DocRouter) error {
+	if vf.LeaveRouterFunc == nil {
+		return nil
+	}
+
+	return vf.LeaveRouterFunc(s, dr)
+}
+
+// VisitRoute implements Visitor.
+func (vf VisitorFuncs) VisitRoute(s WalkState, rt DocRoute) error {
+	if vf.VisitRouteFunc == nil {
+		return nil
+	}
+
+	return vf.VisitRouteFunc(s, rt)
+}
+
+// VisitHandler implements Visitor.
+func (vf VisitorFuncs) VisitHandler(s WalkState, dh DocHandler) error {
+	if vf.VisitHandlerFunc == nil {
+		return nil
+	}
+
+	return vf.VisitHandlerFunc(s, dh)
+}
+
+// VisitMiddleware implements Visitor.
+func (vf VisitorFuncs) VisitMiddleware(s WalkState, mw DocMiddleware) error {
+	if vf.VisitMiddlewareFunc == nil {
+		return nil
+	}
+
+	return vf.VisitMiddlewareFunc(s, mw)
+}
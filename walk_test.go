@@ -0,0 +1,180 @@
+package docgen_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teal-finance/docgen-yes"
+)
+
+// recorder records the calls of Walk, and returns the error of a call.
+func recorder(calls *[]string, errs map[string]error) docgen.VisitorFuncs {
+	record := func(call string, s docgen.WalkState) error {
+		names := make([]string, len(s.Middlewares))
+		for i, mw := range s.Middlewares {
+			names[i] = mw.Func
+		}
+		*calls = append(*calls, fmt.Sprintf("%d %s [%s]", s.Depth, call, strings.Join(names, " ")))
+
+		return errs[call]
+	}
+
+	return docgen.VisitorFuncs{
+		EnterRouterFunc: func(s docgen.WalkState, _ docgen.DocRouter) error {
+			return record("enter "+s.Pattern, s)
+		},
+		LeaveRouterFunc: func(s docgen.WalkState, _ docgen.DocRouter) error {
+			return record("leave "+s.Pattern, s)
+		},
+		VisitRouteFunc: func(s docgen.WalkState, rt docgen.DocRoute) error {
+			return record("route "+s.Pattern+" "+rt.Pattern, s)
+		},
+		VisitHandlerFunc: func(s docgen.WalkState, dh docgen.DocHandler) error {
+			return record("handler "+s.Method+" "+s.Pattern+" "+dh.Method+" "+dh.Func, s)
+		},
+		VisitMiddlewareFunc: func(s docgen.WalkState, mw docgen.DocMiddleware) error {
+			return record("middleware "+s.Method+" "+s.Pattern+" "+mw.Func, s)
+		},
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	errVisit := errors.New("visit error")
+
+	cases := []struct {
+		name    string
+		errs    map[string]error
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "all",
+			errs: nil,
+			want: []string{
+				"0 enter  []",
+				"0 route /articles/* /articles/* []",
+				"1 enter /articles/* []",
+				"1 middleware  /articles/* Log []",
+				"1 route /articles/ / [Log]",
+				"1 handler GET /articles/ GET ListArticles [Log]",
+				"1 handler POST /articles/ POST CreateArticle [Log]",
+				"1 route /articles/{id} /{id} [Log]",
+				"1 handler GET /articles/{id} GET GetArticle [Log]",
+				"1 middleware DELETE /articles/{id} Auth [Log]",
+				"1 handler DELETE /articles/{id} DELETE DeleteArticle [Log Auth]",
+				"1 leave /articles/* []",
+				"0 route /ping /ping []",
+				"0 handler GET /ping GET Ping []",
+				"0 leave  []",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "skip router",
+			errs: map[string]error{"enter /articles/*": docgen.SkipRouter},
+			want: []string{
+				"0 enter  []",
+				"0 route /articles/* /articles/* []",
+				"1 enter /articles/* []",
+				"0 route /ping /ping []",
+				"0 handler GET /ping GET Ping []",
+				"0 leave  []",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "skip route",
+			errs: map[string]error{"route /articles/* /articles/*": docgen.SkipRouter},
+			want: []string{
+				"0 enter  []",
+				"0 route /articles/* /articles/* []",
+				"0 route /ping /ping []",
+				"0 handler GET /ping GET Ping []",
+				"0 leave  []",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "stop",
+			errs: map[string]error{"route /articles/ /": docgen.StopWalk},
+			want: []string{
+				"0 enter  []",
+				"0 route /articles/* /articles/* []",
+				"1 enter /articles/* []",
+				"1 middleware  /articles/* Log []",
+				"1 route /articles/ / [Log]",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error",
+			errs: map[string]error{"middleware DELETE /articles/{id} Auth": errVisit},
+			want: []string{
+				"0 enter  []",
+				"0 route /articles/* /articles/* []",
+				"1 enter /articles/* []",
+				"1 middleware  /articles/* Log []",
+				"1 route /articles/ / [Log]",
+				"1 handler GET /articles/ GET ListArticles [Log]",
+				"1 handler POST /articles/ POST CreateArticle [Log]",
+				"1 route /articles/{id} /{id} [Log]",
+				"1 handler GET /articles/{id} GET GetArticle [Log]",
+				"1 middleware DELETE /articles/{id} Auth [Log]",
+			},
+			wantErr: errVisit,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc := oldDoc()
+			doc.Router.Routes["/articles/*"].Router.Routes["/{id}"] = docgen.DocRoute{Handlers: docgen.DocHandlers{
+				"DELETE": handler("DELETE", "DeleteArticle", "Auth"),
+				"GET":    handler("GET", "GetArticle"),
+			}}
+
+			var calls []string
+			err := docgen.Walk(doc, recorder(&calls, c.errs))
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("Walk() error = %v, want %v", err, c.wantErr)
+			}
+			if !reflect.DeepEqual(calls, c.want) {
+				t.Errorf("Walk() calls =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(c.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWalker_MethodOrder(t *testing.T) {
+	t.Parallel()
+
+	var methods []string
+	walker := docgen.Walker{MethodOrder: []string{"POST", "GET"}}
+	err := walker.Walk(oldDoc(), docgen.VisitorFuncs{
+		EnterRouterFunc: nil,
+		LeaveRouterFunc: nil,
+		VisitRouteFunc:  nil,
+		VisitHandlerFunc: func(s docgen.WalkState, dh docgen.DocHandler) error {
+			methods = append(methods, dh.Method+" "+s.Pattern)
+
+			return nil
+		},
+		VisitMiddlewareFunc: nil,
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	want := []string{"POST /articles/", "GET /articles/", "GET /articles/{id}", "GET /ping"}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("Walk() handlers = %v, want %v", methods, want)
+	}
+}